@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/event/handler"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/graph"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/grpc/pb"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/grpc/service"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/webserver"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGivenEventsDisabled_WhenNewEventDispatcher_ThenShouldNotConnectToRabbitMQ(t *testing.T) {
+	connections := 0
+	dispatcher, err := newEventDispatcher(false, 0, 0, nil, func() handler.Publisher {
+		connections++
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.IsType(t, &events.NoopDispatcher{}, dispatcher)
+	assert.Nil(t, dispatcher.Dispatch(event.NewOrderCreated()))
+	assert.Equal(t, 0, connections)
+}
+
+func TestGivenEventsEnabled_WhenNewEventDispatcher_ThenShouldRegisterTheRabbitMQHandler(t *testing.T) {
+	connections := 0
+	dispatcher, err := newEventDispatcher(true, 0, 0, nil, func() handler.Publisher {
+		connections++
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.IsType(t, &events.EventDispatcher{}, dispatcher)
+	assert.Equal(t, 1, connections)
+}
+
+func TestGivenAHandlerLimitBelowTheBuiltInHandlers_WhenRegisteringForOrderChanges_ThenShouldFail(t *testing.T) {
+	dispatcher, err := newEventDispatcher(true, 1, 0, nil, func() handler.Publisher { return nil })
+	assert.Nil(t, err)
+
+	err = registerForOrderChanges(dispatcher, usecase.NewGetTotalRevenueUseCase(nil))
+
+	assert.ErrorIs(t, err, events.ErrTooManyHandlers)
+	assert.EqualError(t, err, "EVENT_MAX_HANDLERS is too low for the handlers registered for OrderCreated at startup: too many handlers registered for event")
+}
+
+func TestGivenAMaxRecvMsgSize_WhenALargerRequestArrives_ThenShouldRejectIt(t *testing.T) {
+	server := newGRPCServer(1024, 1024, false)
+	pb.RegisterOrderServiceServer(server, service.NewOrderService(usecase.CreateOrderUseCase{}, usecase.ListOrdersUseCase{}))
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	_, err = pb.NewOrderServiceClient(conn).CreateOrder(context.Background(), &pb.CreateOrderRequest{Id: strings.Repeat("x", 2048)})
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestGivenReflectionDisabled_WhenNewGRPCServer_ThenShouldNotAdvertiseTheServices(t *testing.T) {
+	server := newGRPCServer(1024, 1024, false)
+	_, registered := server.GetServiceInfo()["grpc.reflection.v1.ServerReflection"]
+	assert.False(t, registered)
+
+	server = newGRPCServer(1024, 1024, true)
+	_, registered = server.GetServiceInfo()["grpc.reflection.v1.ServerReflection"]
+	assert.True(t, registered)
+}
+
+func TestGivenOnlyThePOSTTransport_WhenAGETQueryArrives_ThenShouldRejectIt(t *testing.T) {
+	srv, err := newGraphQLServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}), []string{"post"}, 0, false)
+	assert.Nil(t, err)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?query={__typename}", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "transport not supported")
+
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"{__typename}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGivenAnUnknownTransport_WhenNewGraphQLServer_ThenShouldFail(t *testing.T) {
+	_, err := newGraphQLServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}), []string{"sse"}, 0, false)
+
+	assert.EqualError(t, err, `unknown graphql transport "sse"`)
+}
+
+func TestGivenGraphQLOnTheWebServer_WhenRequestingRESTAndGraphQL_ThenBothShouldRespond(t *testing.T) {
+	srv, err := newGraphQLServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}), []string{"post"}, 0, false)
+	assert.Nil(t, err)
+	ws := webserver.NewWebServer(":0")
+	ws.AddHandler(http.MethodGet, "/order", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orders":[]}`))
+	})
+	mountGraphQL(ws, srv)
+
+	rec := httptest.NewRecorder()
+	ws.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"orders":[]}`, rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"{__typename}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	ws.Router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data":{"__typename":"Query"}}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	ws.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/playground", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGivenSecurityHeaders_WhenRequestingThePlayground_ThenShouldRelaxOnlyItsPolicy(t *testing.T) {
+	srv, err := newGraphQLServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{}}), []string{"post"}, 0, false)
+	assert.Nil(t, err)
+	ws := webserver.NewWebServer(":0")
+	ws.SecurityHeaders = true
+	ws.ContentSecurityPolicy = "default-src 'none'"
+	mountGraphQL(ws, srv)
+	standalone := withSecurityHeaders(true, "default-src 'none'", newGraphQLMux(srv))
+
+	for name, h := range map[string]http.Handler{"web server": ws.Router, "standalone": standalone} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/playground", nil))
+		assert.Equal(t, http.StatusOK, rec.Code, name)
+		assert.Equal(t, webserver.PlaygroundCSP, rec.Header().Get("Content-Security-Policy"), name)
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"), name)
+
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"{__typename}"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, "default-src 'none'", rec.Header().Get("Content-Security-Policy"), name)
+	}
+}
+
+func TestGivenTheStandaloneGraphQLServer_WhenRequestingThePlaygroundAndTheRoot_ThenOnlyThePlaygroundShouldServeTheConsole(t *testing.T) {
+	mux := newGraphQLMux(http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/playground", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "GraphQL playground")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/playground", rec.Header().Get("Location"))
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGivenRuleNames_WhenNewOrderRules_ThenShouldBuildThemOrRejectUnknownOnes(t *testing.T) {
+	rules, err := newOrderRules([]string{"tax_not_above_price", "approval_above=1000"})
+	assert.Nil(t, err)
+	assert.Len(t, rules, 2)
+
+	_, err = newOrderRules([]string{"weekend_only"})
+	assert.EqualError(t, err, `unknown order rule "weekend_only"`)
+}
@@ -32,7 +32,7 @@ var setOrderCreatedEvent = wire.NewSet(
 	wire.Bind(new(events.EventInterface), new(*event.OrderCreated)),
 )
 
-func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
+func NewCreateOrderUseCase(db *sql.DB, repositoryConfig database.RepositoryConfig, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
 	wire.Build(
 		setOrderRepositoryDependency,
 		setOrderCreatedEvent,
@@ -41,7 +41,7 @@ func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInt
 	return &usecase.CreateOrderUseCase{}
 }
 
-func NewListOrdersUseCase(db *sql.DB) *usecase.ListOrdersUseCase {
+func NewListOrdersUseCase(db *sql.DB, repositoryConfig database.RepositoryConfig) *usecase.ListOrdersUseCase {
 	wire.Build(
 		setOrderRepositoryDependency,
 		usecase.NewListOrdersUseCase,
@@ -49,7 +49,7 @@ func NewListOrdersUseCase(db *sql.DB) *usecase.ListOrdersUseCase {
 	return &usecase.ListOrdersUseCase{}
 }
 
-func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
+func NewWebOrderHandler(db *sql.DB, repositoryConfig database.RepositoryConfig, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
 	wire.Build(
 		setOrderRepositoryDependency,
 		setOrderCreatedEvent,
@@ -23,21 +23,21 @@ import (
 
 // Injectors from wire.go:
 
-func NewCreateOrderUseCase(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
-	orderRepository := database.NewOrderRepository(db)
+func NewCreateOrderUseCase(db *sql.DB, repositoryConfig database.RepositoryConfig, eventDispatcher events.EventDispatcherInterface) *usecase.CreateOrderUseCase {
+	orderRepository := database.NewOrderRepository(db, repositoryConfig)
 	orderCreated := event.NewOrderCreated()
 	createOrderUseCase := usecase.NewCreateOrderUseCase(orderRepository, orderCreated, eventDispatcher)
 	return createOrderUseCase
 }
 
-func NewListOrdersUseCase(db *sql.DB) *usecase.ListOrdersUseCase {
-	orderRepository := database.NewOrderRepository(db)
+func NewListOrdersUseCase(db *sql.DB, repositoryConfig database.RepositoryConfig) *usecase.ListOrdersUseCase {
+	orderRepository := database.NewOrderRepository(db, repositoryConfig)
 	listOrdersUseCase := usecase.NewListOrdersUseCase(orderRepository)
 	return listOrdersUseCase
 }
 
-func NewWebOrderHandler(db *sql.DB, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
-	orderRepository := database.NewOrderRepository(db)
+func NewWebOrderHandler(db *sql.DB, repositoryConfig database.RepositoryConfig, eventDispatcher events.EventDispatcherInterface) *web.WebOrderHandler {
+	orderRepository := database.NewOrderRepository(db, repositoryConfig)
 	orderCreated := event.NewOrderCreated()
 	webOrderHandler := web.NewWebOrderHandler(eventDispatcher, orderRepository, orderCreated)
 	return webOrderHandler
@@ -0,0 +1,37 @@
+package configs
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Features groups the optional behaviours operators switch on and off. The
+// keys stay flat in the environment; the struct only groups them in code.
+type Features struct {
+	EventsEnabled       bool          `mapstructure:"EVENTS_ENABLED"`
+	BatchAllOrNothing   bool          `mapstructure:"BATCH_ALL_OR_NOTHING"`
+	ListCacheMaxAge     time.Duration `mapstructure:"LIST_CACHE_MAX_AGE"`
+	ListSnapshot        bool          `mapstructure:"LIST_SNAPSHOT"`
+	ListConditional     bool          `mapstructure:"LIST_CONDITIONAL"`
+	ListNDJSON          bool          `mapstructure:"LIST_NDJSON"`
+	GraphQLOnWebServer  bool          `mapstructure:"GRAPHQL_ON_WEB_SERVER"`
+	SearchCaseSensitive bool          `mapstructure:"ORDER_SEARCH_CASE_SENSITIVE"`
+	SecurityHeaders     bool          `mapstructure:"SECURITY_HEADERS"`
+	FastJSON            bool          `mapstructure:"JSON_FAST_MARSHAL"`
+	MigrationForceDirty bool          `mapstructure:"MIGRATION_FORCE_DIRTY"`
+}
+
+func setFeatureDefaults() {
+	viper.SetDefault("EVENTS_ENABLED", true)
+	viper.SetDefault("BATCH_ALL_OR_NOTHING", true)
+	viper.SetDefault("LIST_CACHE_MAX_AGE", "0s")
+	viper.SetDefault("LIST_SNAPSHOT", false)
+	viper.SetDefault("LIST_CONDITIONAL", false)
+	viper.SetDefault("LIST_NDJSON", false)
+	viper.SetDefault("GRAPHQL_ON_WEB_SERVER", false)
+	viper.SetDefault("ORDER_SEARCH_CASE_SENSITIVE", false)
+	viper.SetDefault("SECURITY_HEADERS", false)
+	viper.SetDefault("JSON_FAST_MARSHAL", false)
+	viper.SetDefault("MIGRATION_FORCE_DIRTY", false)
+}
@@ -0,0 +1,37 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenSomeToggles_WhenLoadConfig_ThenShouldDefaultTheRest(t *testing.T) {
+	dir := t.TempDir()
+	env := "EVENTS_ENABLED=false\nLIST_SNAPSHOT=true\nLIST_CACHE_MAX_AGE=30s\n"
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0o600))
+	t.Chdir(dir)
+
+	cfg, err := LoadConfig(dir)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Features{
+		EventsEnabled:     false,
+		BatchAllOrNothing: true,
+		ListCacheMaxAge:   30 * time.Second,
+		ListSnapshot:      true,
+	}, cfg.Features)
+}
+
+func TestGivenASubSecondCacheMaxAge_WhenLoadConfig_ThenShouldFail(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("LIST_CACHE_MAX_AGE=1500ms\n"), 0o600))
+	t.Chdir(dir)
+
+	_, err := LoadConfig(dir)
+
+	assert.EqualError(t, err, "LIST_CACHE_MAX_AGE must be whole seconds, got 1.5s")
+}
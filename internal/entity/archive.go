@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// OrderArchiver moves orders out of the orders table into the archive.
+type OrderArchiver interface {
+	// ArchiveBefore moves up to limit of the oldest orders created before
+	// cutoff to the archive, stamped with archivedAt, and deletes them from
+	// the orders table in the same transaction. It returns how many moved.
+	ArchiveBefore(ctx context.Context, cutoff time.Time, limit int, archivedAt time.Time) (int, error)
+}
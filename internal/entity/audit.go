@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// Actions recorded in an order's audit trail.
+const (
+	OrderChangeCreated       = "created"
+	OrderChangeUpdated       = "updated"
+	OrderChangeStatusChanged = "status_changed"
+)
+
+// OrderChange is one entry of an order's audit trail: what happened to the
+// order and the status it was left in.
+type OrderChange struct {
+	OrderID   string
+	Action    string
+	Status    string
+	ChangedAt time.Time
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "fmt"
+
+// BatchItemError reports which item of a batch failed and why.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// OrderChangeCursor is a position in the (updated_at, id) order changes are
+// read in. A cursor without an ID stands before every order updated at
+// UpdatedAt, so it starts a sync from a point in time.
+type OrderChangeCursor struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+// NextOrderChangeCursor returns the cursor positioned on order, the last one a
+// page returned.
+func NextOrderChangeCursor(order Order) OrderChangeCursor {
+	return OrderChangeCursor{UpdatedAt: order.UpdatedAt, ID: order.ID}
+}
@@ -0,0 +1,16 @@
+// Package entitytest holds test helpers for code that returns domain errors.
+package entitytest
+
+import (
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertDomainError asserts that err matches target and is a domain error.
+func AssertDomainError(t assert.TestingT, err, target error, msgAndArgs ...interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	return assert.ErrorIs(t, err, target, msgAndArgs...) &&
+		assert.ErrorIs(t, err, entity.ErrDomain, msgAndArgs...)
+}
@@ -0,0 +1,59 @@
+package entity
+
+import "errors"
+
+// ErrDomain is matched by every domain error, so callers can tell a broken
+// business rule from an infrastructure failure with errors.Is(err, ErrDomain).
+var ErrDomain = errors.New("domain error")
+
+type domainError struct {
+	message string
+}
+
+func (e *domainError) Error() string {
+	return e.message
+}
+
+func (e *domainError) Is(target error) bool {
+	return target == ErrDomain
+}
+
+func newDomainError(message string) error {
+	return &domainError{message: message}
+}
+
+// Validation errors returned by Order.IsValid.
+var (
+	ErrInvalidID     = newDomainError("invalid id")
+	ErrInvalidPrice  = newDomainError("invalid price")
+	ErrInvalidTax    = newDomainError("invalid tax")
+	ErrInvalidStatus = newDomainError("invalid status")
+)
+
+// Storage errors returned by repositories, wrapping the driver error. They are
+// infrastructure failures, not domain errors, so they never match ErrDomain.
+var (
+	// ErrOrderAlreadyExists is returned when storage already holds an order
+	// with the ID being inserted.
+	ErrOrderAlreadyExists = errors.New("order already exists")
+	// ErrStorageUnavailable is returned when storage cannot be reached.
+	ErrStorageUnavailable = errors.New("storage unavailable")
+)
+
+// ErrOrderNotFound is returned when no order has the requested ID.
+var ErrOrderNotFound = newDomainError("order not found")
+
+// ErrIDPrefixTooShort is returned when an ID prefix search is given fewer
+// characters than the repository requires.
+var ErrIDPrefixTooShort = newDomainError("id prefix is too short")
+
+// ErrInvalidStatusTransition is returned when orders are asked to move to a
+// status their current status does not allow.
+var ErrInvalidStatusTransition = newDomainError("invalid status transition")
+
+// ErrBatchTooLarge is returned when a batch has more orders than allowed.
+var ErrBatchTooLarge = newDomainError("batch has too many orders")
+
+// ErrInvalidExportField is returned when an export asks for a field outside
+// OrderExportFields, or for the same field twice.
+var ErrInvalidExportField = newDomainError("invalid export field")
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertDomainError mirrors entitytest.AssertDomainError, which this package
+// cannot import.
+func assertDomainError(t *testing.T, err, target error) {
+	t.Helper()
+	assert.ErrorIs(t, err, target)
+	assert.ErrorIs(t, err, ErrDomain)
+}
+
+func TestGivenAWrappedDomainError_WhenMatched_ThenShouldMatchItAndErrDomain(t *testing.T) {
+	err := fmt.Errorf("item 1: %w", ErrInvalidPrice)
+
+	assertDomainError(t, err, ErrInvalidPrice)
+	assert.NotErrorIs(t, err, ErrInvalidTax)
+	assert.Equal(t, "item 1: invalid price", err.Error())
+}
+
+func TestGivenAnInfrastructureError_WhenMatched_ThenShouldNotBeADomainError(t *testing.T) {
+	assert.NotErrorIs(t, errors.New("connection refused"), ErrDomain)
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// OrderExportFields are the fields an order export can include, in the order
+// a full export writes them. Each is also the name of its column.
+var OrderExportFields = []string{"id", "price", "tax", "final_price", "status", "created_at", "updated_at", "trace_id"}
+
+// ValidateExportFields returns ErrInvalidExportField for the first field that
+// is unknown or repeated.
+func ValidateExportFields(fields []string) error {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known := false
+		for _, f := range OrderExportFields {
+			known = known || f == field
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown field %q", ErrInvalidExportField, field)
+		}
+		if seen[field] {
+			return fmt.Errorf("%w: %q is listed twice", ErrInvalidExportField, field)
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// OrderExporter streams every order to w as newline-delimited JSON objects
+// holding only fields, or all OrderExportFields when none are given.
+// Timestamps are written in UTC with the timeLayout time layout.
+type OrderExporter interface {
+	Export(ctx context.Context, w io.Writer, timeLayout string, fields ...string) error
+}
@@ -1,6 +1,63 @@
 package entity
 
+import (
+	"context"
+	"time"
+)
+
+// OrderStatusFilter selects the orders in Status created before CreatedBefore.
+// A zero CreatedBefore matches every order in Status.
+type OrderStatusFilter struct {
+	Status        string
+	CreatedBefore time.Time
+}
+
+// OrderSetVersion identifies the state of all orders: no order can be created
+// or updated without changing it, to the second.
+type OrderSetVersion struct {
+	Count         int
+	LastUpdatedAt time.Time
+}
+
+// OrderRepositoryInterface reads orders back as stored. Validation only runs
+// when orders are created, so legacy rows that no longer pass IsValid, such as
+// a zero price, stay readable.
 type OrderRepositoryInterface interface {
-	Save(order *Order) error
-	FindAll() ([]Order, error)
+	Save(ctx context.Context, order *Order) error
+	SaveOrUpdate(ctx context.Context, order *Order) error
+	// SaveBatch inserts all orders in a single transaction. On failure nothing
+	// is persisted and the error is a *BatchItemError for the failing order.
+	SaveBatch(ctx context.Context, orders []*Order) error
+	// FindByID returns ErrOrderNotFound when no order has the given ID.
+	FindByID(ctx context.Context, id string) (*Order, error)
+	FindAll(ctx context.Context) ([]Order, error)
+	FindPage(ctx context.Context, limit, offset int) ([]Order, error)
+	// FindPageWithTotal is FindPage plus the number of orders across all pages.
+	FindPageWithTotal(ctx context.Context, limit, offset int) ([]Order, int, error)
+	// FindPageAsOf is FindPage restricted to the orders created before asOf,
+	// so orders created later do not shift the pages. It is a cutoff, not a
+	// transaction: deleted or archived orders still shift the offsets of the
+	// following pages, and a client paging past them skips as many rows.
+	FindPageAsOf(ctx context.Context, asOf time.Time, limit, offset int) ([]Order, error)
+	FindByStatus(ctx context.Context, status string, limit int) ([]Order, error)
+	CountByStatus(ctx context.Context) (map[string]int, error)
+	// Version returns the number of orders and the latest updated_at.
+	Version(ctx context.Context) (OrderSetVersion, error)
+	FindRecent(ctx context.Context, n int) ([]Order, error)
+	// FindMostExpensive returns the order with the highest final price, or
+	// ErrOrderNotFound when there are no orders.
+	FindMostExpensive(ctx context.Context) (*Order, error)
+	// FindUpdatedSince returns up to limit orders positioned after the cursor,
+	// ordered by updated_at and then ID.
+	FindUpdatedSince(ctx context.Context, after OrderChangeCursor, limit int) ([]Order, error)
+	// SearchByIDPrefix returns up to limit orders whose ID starts with prefix,
+	// or ErrIDPrefixTooShort when prefix is below the configured minimum.
+	SearchByIDPrefix(ctx context.Context, prefix string, limit int) ([]Order, error)
+	// UpdateStatusWhere moves every order matching filter to status in a single
+	// statement and returns how many orders were updated.
+	UpdateStatusWhere(ctx context.Context, filter OrderStatusFilter, status string, updatedAt time.Time) (int, error)
+	// FindHistory returns the audit trail of the order, oldest change first.
+	// Save, SaveOrUpdate, SaveBatch and UpdateStatusWhere append to it in the
+	// same transaction as the change.
+	FindHistory(ctx context.Context, id string) ([]OrderChange, error)
 }
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"context"
+	"iter"
+)
+
+// OrderIterator reads the stored orders one at a time, for lists too large to
+// load at once.
+type OrderIterator interface {
+	// Orders yields every order, ordered by created_at and then ID like the
+	// pages of FindPage. Iteration stops after the first error, which is
+	// yielded with a zero Order.
+	Orders(ctx context.Context) iter.Seq2[Order, error]
+}
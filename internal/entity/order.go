@@ -1,19 +1,55 @@
 package entity
 
-import "errors"
+import (
+	"math"
+	"time"
+)
+
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusProcessing = "processing"
+	OrderStatusShipped    = "shipped"
+	OrderStatusDelivered  = "delivered"
+	OrderStatusCancelled  = "cancelled"
+)
+
+// OrderStatuses is the known set of statuses, in lifecycle order.
+var OrderStatuses = []string{
+	OrderStatusPending,
+	OrderStatusProcessing,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCancelled,
+}
+
+// orderStatusTransitions lists the statuses each status may move to.
+var orderStatusTransitions = map[string][]string{
+	OrderStatusPending:    {OrderStatusProcessing, OrderStatusCancelled},
+	OrderStatusProcessing: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:    {OrderStatusDelivered},
+}
 
 type Order struct {
 	ID         string
 	Price      float64
 	Tax        float64
 	FinalPrice float64
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	// TraceID is the ID of the request that created the order, when known.
+	TraceID string
 }
 
 func NewOrder(id string, price float64, tax float64) (*Order, error) {
+	now := time.Now().UTC().Truncate(time.Second)
 	order := &Order{
-		ID:    id,
-		Price: price,
-		Tax:   tax,
+		ID:        id,
+		Price:     price,
+		Tax:       tax,
+		Status:    OrderStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 	err := order.IsValid()
 	if err != nil {
@@ -24,22 +60,52 @@ func NewOrder(id string, price float64, tax float64) (*Order, error) {
 
 func (o *Order) IsValid() error {
 	if o.ID == "" {
-		return errors.New("invalid id")
+		return ErrInvalidID
 	}
 	if o.Price <= 0 {
-		return errors.New("invalid price")
+		return ErrInvalidPrice
 	}
 	if o.Tax <= 0 {
-		return errors.New("invalid tax")
+		return ErrInvalidTax
+	}
+	if o.Status != "" && !IsKnownOrderStatus(o.Status) {
+		return ErrInvalidStatus
 	}
 	return nil
 }
 
 func (o *Order) CalculateFinalPrice() error {
-	o.FinalPrice = o.Price + o.Tax
+	o.FinalPrice = RoundPrice(o.Price + o.Tax)
 	err := o.IsValid()
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// RoundPrice rounds value to two decimal places, halves away from zero. It
+// first rounds at a finer precision so that binary representation noise, as in
+// 1.005 being stored as 1.00499999..., does not flip the result.
+func RoundPrice(value float64) float64 {
+	return math.Round(math.Round(value*1e8)/1e6) / 100
+}
+
+func IsKnownOrderStatus(status string) bool {
+	for _, s := range OrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionOrderStatus reports whether an order in status from may move to
+// status to. Delivered and cancelled orders are final.
+func CanTransitionOrderStatus(from, to string) bool {
+	for _, s := range orderStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
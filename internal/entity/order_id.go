@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxOrderIDLength is the size of the orders.id column, the longest ID
+// NormalizeOrderID ever accepts.
+const MaxOrderIDLength = 255
+
+// NormalizeOrderID trims the whitespace around an order ID taken from a
+// request and returns ErrInvalidID when nothing is left, when it is longer
+// than maxLength, or when it contains whitespace or control characters. A
+// maxLength of zero or less means MaxOrderIDLength. Every transport checks IDs
+// through it before reaching a use case.
+func NormalizeOrderID(id string, maxLength int) (string, error) {
+	if maxLength <= 0 {
+		maxLength = MaxOrderIDLength
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", fmt.Errorf("%w: id must not be empty", ErrInvalidID)
+	}
+	if len(id) > maxLength {
+		return "", fmt.Errorf("%w: id is longer than %d characters", ErrInvalidID, maxLength)
+	}
+	if strings.ContainsFunc(id, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		return "", fmt.Errorf("%w: id must not contain whitespace or control characters", ErrInvalidID)
+	}
+	return id, nil
+}
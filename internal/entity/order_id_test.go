@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenEmptyOrWhitespaceIDs_WhenNormalizeOrderID_ThenShouldRejectThem(t *testing.T) {
+	for _, id := range []string{"", " ", "\t\n", "order 1", "order\x00", strings.Repeat("x", MaxOrderIDLength+1)} {
+		t.Run(id, func(t *testing.T) {
+			_, err := NormalizeOrderID(id, 0)
+			assertDomainError(t, err, ErrInvalidID)
+		})
+	}
+}
+
+func TestGivenAPaddedID_WhenNormalizeOrderID_ThenShouldTrimIt(t *testing.T) {
+	id, err := NormalizeOrderID("  order-001\n", 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "order-001", id)
+}
+
+func TestGivenAMaxLength_WhenNormalizeOrderID_ThenShouldRejectLongerIDs(t *testing.T) {
+	_, err := NormalizeOrderID("order-0001", 9)
+	assertDomainError(t, err, ErrInvalidID)
+
+	id, err := NormalizeOrderID("order-001", 9)
+	assert.Nil(t, err)
+	assert.Equal(t, "order-001", id)
+}
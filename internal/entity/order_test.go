@@ -8,17 +8,17 @@ import (
 
 func TestGivenAnEmptyID_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
 	order := Order{}
-	assert.Error(t, order.IsValid(), "invalid id")
+	assertDomainError(t, order.IsValid(), ErrInvalidID)
 }
 
 func TestGivenAnEmptyPrice_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
 	order := Order{ID: "123"}
-	assert.Error(t, order.IsValid(), "invalid price")
+	assertDomainError(t, order.IsValid(), ErrInvalidPrice)
 }
 
 func TestGivenAnEmptyTax_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
 	order := Order{ID: "123", Price: 10}
-	assert.Error(t, order.IsValid(), "invalid tax")
+	assertDomainError(t, order.IsValid(), ErrInvalidTax)
 }
 
 func TestGivenAValidParams_WhenICallNewOrder_ThenIShouldReceiveCreateOrderWithAllParams(t *testing.T) {
@@ -47,3 +47,41 @@ func TestGivenAPriceAndTax_WhenICallCalculatePrice_ThenIShouldSetFinalPrice(t *t
 	assert.Nil(t, order.CalculateFinalPrice())
 	assert.Equal(t, 12.0, order.FinalPrice)
 }
+
+func TestGivenAValidParams_WhenICallNewOrderFunc_ThenIShouldReceivePendingStatus(t *testing.T) {
+	order, err := NewOrder("123", 10.0, 2.0)
+	assert.Nil(t, err)
+	assert.Equal(t, OrderStatusPending, order.Status)
+}
+
+func TestGivenAnUnknownStatus_WhenCreateANewOrder_ThenShouldReceiveAnError(t *testing.T) {
+	order := Order{ID: "123", Price: 10, Tax: 2, Status: "lost"}
+	assertDomainError(t, order.IsValid(), ErrInvalidStatus)
+}
+
+func TestGivenOrderStatuses_WhenCanTransitionOrderStatus_ThenShouldFollowTheLifecycle(t *testing.T) {
+	assert.True(t, CanTransitionOrderStatus(OrderStatusPending, OrderStatusProcessing))
+	assert.True(t, CanTransitionOrderStatus(OrderStatusPending, OrderStatusCancelled))
+	assert.True(t, CanTransitionOrderStatus(OrderStatusShipped, OrderStatusDelivered))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusPending, OrderStatusShipped))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusDelivered, OrderStatusCancelled))
+	assert.False(t, CanTransitionOrderStatus(OrderStatusCancelled, OrderStatusPending))
+}
+
+func TestGivenPricesWithFloatNoise_WhenCalculateFinalPrice_ThenShouldRoundToCents(t *testing.T) {
+	tests := []struct {
+		price, tax, want float64
+	}{
+		{0.1, 0.2, 0.3},
+		{10.1, 0.2, 10.3},
+		{100.5, 10.05, 110.55},
+		{1.004, 1, 2},
+		{1.005, 1, 2.01},
+		{0.125, 0.5, 0.63},
+	}
+	for _, tt := range tests {
+		order := Order{ID: "123", Price: tt.price, Tax: tt.tax}
+		assert.Nil(t, order.CalculateFinalPrice())
+		assert.Equal(t, tt.want, order.FinalPrice, "%v + %v", tt.price, tt.tax)
+	}
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "context"
+
+// OrderRevenueReader sums the revenue of the stored orders.
+type OrderRevenueReader interface {
+	// TotalRevenue returns the sum of the final prices of every order that
+	// was not cancelled.
+	TotalRevenue(ctx context.Context) (float64, error)
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrTaxExceedsPrice is returned by TaxNotAbovePrice.
+var ErrTaxExceedsPrice = newDomainError("tax exceeds price")
+
+// ErrApprovalRequired is returned by RequireApprovalAbove. Orders needing
+// approval cannot be created until an approval flow exists.
+var ErrApprovalRequired = newDomainError("order requires approval")
+
+// OrderRule checks a business rule on an order about to be created. It returns
+// nil when the order complies and a domain error otherwise.
+type OrderRule func(order Order) error
+
+// RuleViolationsError lists every rule an order broke. errors.Is matches each
+// of the violations.
+type RuleViolationsError struct {
+	Violations []error
+}
+
+func (e *RuleViolationsError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, err := range e.Violations {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *RuleViolationsError) Unwrap() []error {
+	return e.Violations
+}
+
+// EvaluateOrderRules runs every rule against order and returns a
+// *RuleViolationsError with all violations, or nil when there are none.
+func EvaluateOrderRules(order Order, rules []OrderRule) error {
+	var violations []error
+	for _, rule := range rules {
+		if err := rule(order); err != nil {
+			violations = append(violations, err)
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &RuleViolationsError{Violations: violations}
+}
+
+// TaxNotAbovePrice rejects orders taxed more than their price.
+func TaxNotAbovePrice(order Order) error {
+	if order.Tax > order.Price {
+		return ErrTaxExceedsPrice
+	}
+	return nil
+}
+
+// RequireApprovalAbove rejects orders priced above limit.
+func RequireApprovalAbove(limit float64) OrderRule {
+	return func(order Order) error {
+		if order.Price > limit {
+			return fmt.Errorf("%w: price %.2f is above %.2f", ErrApprovalRequired, order.Price, limit)
+		}
+		return nil
+	}
+}
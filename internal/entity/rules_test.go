@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenRules_WhenEvaluateOrderRules_ThenShouldCollectEveryViolation(t *testing.T) {
+	rules := []OrderRule{TaxNotAbovePrice, RequireApprovalAbove(1000)}
+
+	assert.Nil(t, EvaluateOrderRules(Order{ID: "ok", Price: 100, Tax: 10}, rules))
+
+	err := EvaluateOrderRules(Order{ID: "taxed", Price: 100, Tax: 150}, rules)
+	assertDomainError(t, err, ErrTaxExceedsPrice)
+	assert.NotErrorIs(t, err, ErrApprovalRequired)
+
+	err = EvaluateOrderRules(Order{ID: "both", Price: 2000, Tax: 2500}, rules)
+	assertDomainError(t, err, ErrTaxExceedsPrice)
+	assertDomainError(t, err, ErrApprovalRequired)
+	assert.EqualError(t, err, "tax exceeds price; order requires approval: price 2000.00 is above 1000.00")
+}
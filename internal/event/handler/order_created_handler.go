@@ -3,17 +3,27 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"sync"
 
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/metrics"
 	"github.com/mvr-garcia/go-clean-arch/pkg/events"
 	"github.com/streadway/amqp"
 )
 
+// Publisher is the part of *amqp.Channel used to publish events.
+type Publisher interface {
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
 type OrderCreatedHandler struct {
-	RabbitMQChannel *amqp.Channel
+	RabbitMQChannel Publisher
+	// RoutingKey builds the key each event is published with. When nil, events
+	// are published with the empty key.
+	RoutingKey *RoutingKeyTemplate
 }
 
-func NewOrderCreatedHandler(rabbitMQChannel *amqp.Channel) *OrderCreatedHandler {
+func NewOrderCreatedHandler(rabbitMQChannel Publisher) *OrderCreatedHandler {
 	return &OrderCreatedHandler{
 		RabbitMQChannel: rabbitMQChannel,
 	}
@@ -26,14 +36,25 @@ func (h *OrderCreatedHandler) Handle(event events.EventInterface, wg *sync.WaitG
 
 	msgRabbitmq := amqp.Publishing{
 		ContentType: "application/json",
+		Timestamp:   event.GetDateTime(),
 		Body:        jsonOutput,
 	}
+	if identified, ok := event.(events.IdentifiedEventInterface); ok {
+		msgRabbitmq.MessageId = identified.GetEventID()
+	}
 
-	h.RabbitMQChannel.Publish(
+	routingKey := h.RoutingKey.Render(event.GetName(), jsonOutput)
+	err := h.RabbitMQChannel.Publish(
 		"amq.direct", // exchange
-		"",           // key name
+		routingKey,   // key name
 		false,        // mandatory
 		false,        // immediate
 		msgRabbitmq,  // message to publish
 	)
+	if err != nil {
+		slog.Error("failed to publish event", "event", event.GetName(), "error", err)
+		metrics.EventsDispatched.WithLabelValues(event.GetName(), metrics.OutcomeFailure).Inc()
+		return
+	}
+	metrics.EventsDispatched.WithLabelValues(event.GetName(), metrics.OutcomeSuccess).Inc()
 }
@@ -0,0 +1,39 @@
+//go:build integration
+
+package handler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/infratest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenABrokerWithABoundQueue_WhenHandle_ThenTheQueueShouldReceiveTheEvent(t *testing.T) {
+	ch, err := infratest.RabbitMQ(t).Channel()
+	assert.Nil(t, err)
+	queue, err := ch.QueueDeclare("", false, true, true, false, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, ch.QueueBind(queue.Name, "", "amq.direct", false, nil))
+	deliveries, err := ch.Consume(queue.Name, "", true, true, false, false, nil)
+	assert.Nil(t, err)
+
+	orderCreated := event.NewOrderCreated()
+	orderCreated.SetPayload(map[string]string{"id": "123"})
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	NewOrderCreatedHandler(ch).Handle(orderCreated, wg)
+	wg.Wait()
+
+	select {
+	case delivery := <-deliveries:
+		assert.Equal(t, "application/json", delivery.ContentType)
+		assert.Equal(t, orderCreated.GetEventID(), delivery.MessageId)
+		assert.JSONEq(t, `{"id":"123"}`, string(delivery.Body))
+	case <-time.After(10 * time.Second):
+		t.Fatal("no message delivered")
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+type PublisherStub struct {
+	Err       error
+	Published []amqp.Publishing
+}
+
+func (p *PublisherStub) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	p.Published = append(p.Published, msg)
+	return p.Err
+}
+
+func handle(t *testing.T, publisher Publisher) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	NewOrderCreatedHandler(publisher).Handle(event.NewOrderCreated(), wg)
+	wg.Wait()
+}
+
+func TestGivenPublishOutcomes_WhenHandle_ThenShouldCountThemByEventAndOutcome(t *testing.T) {
+	success := metrics.EventsDispatched.WithLabelValues("OrderCreated", metrics.OutcomeSuccess)
+	failure := metrics.EventsDispatched.WithLabelValues("OrderCreated", metrics.OutcomeFailure)
+	successBefore, failureBefore := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+
+	handle(t, &PublisherStub{})
+	handle(t, &PublisherStub{})
+	handle(t, &PublisherStub{Err: errors.New("channel closed")})
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(success)-successBefore)
+	assert.Equal(t, 1.0, testutil.ToFloat64(failure)-failureBefore)
+}
+
+func TestGivenAnOrderCreatedEvent_WhenHandle_ThenShouldPublishItsIDAndTime(t *testing.T) {
+	publisher := &PublisherStub{}
+	orderCreated := event.NewOrderCreated()
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	NewOrderCreatedHandler(publisher).Handle(orderCreated, wg)
+	wg.Wait()
+
+	assert.Len(t, publisher.Published, 1)
+	assert.Equal(t, orderCreated.EventID, publisher.Published[0].MessageId)
+	assert.Equal(t, orderCreated.OccurredAt, publisher.Published[0].Timestamp)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// routingKeyVariables are the placeholders a routing key template may use.
+// eventName is the event's name; the others are read from its JSON payload.
+var routingKeyVariables = map[string]bool{
+	"eventName": true,
+	"id":        true,
+	"status":    true,
+}
+
+var routingKeyPlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// RoutingKeyTemplate renders a RabbitMQ routing key such as
+// "order.{eventName}.{status}" from an event.
+type RoutingKeyTemplate struct {
+	template string
+}
+
+// ParseRoutingKeyTemplate validates template so that unknown variables are
+// reported at startup rather than when the first event is published.
+func ParseRoutingKeyTemplate(template string) (*RoutingKeyTemplate, error) {
+	for _, match := range routingKeyPlaceholder.FindAllStringSubmatch(template, -1) {
+		if !routingKeyVariables[match[1]] {
+			return nil, fmt.Errorf("unknown routing key variable %q", match[1])
+		}
+	}
+	return &RoutingKeyTemplate{template: template}, nil
+}
+
+// Render returns the routing key for an event with the given name and JSON
+// encoded payload. Fields missing from the payload render as empty strings. A
+// nil template renders the empty key.
+func (t *RoutingKeyTemplate) Render(eventName string, payload []byte) string {
+	if t == nil {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	_ = json.Unmarshal(payload, &fields)
+	return routingKeyPlaceholder.ReplaceAllStringFunc(t.template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		if name == "eventName" {
+			return eventName
+		}
+		if value, ok := fields[name]; ok && value != nil {
+			return fmt.Sprint(value)
+		}
+		return ""
+	})
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnOrderPayload_WhenRenderingTheRoutingKey_ThenShouldUseTheOrderFields(t *testing.T) {
+	template, err := ParseRoutingKeyTemplate("order.{eventName}.{status}.{id}")
+	assert.Nil(t, err)
+	payload, err := json.Marshal(usecase.OrderOutputDTO{ID: "order-001", Status: "pending"})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "order.OrderCreated.pending.order-001", template.Render("OrderCreated", payload))
+}
+
+func TestGivenAnUnknownVariable_WhenParsingTheRoutingKeyTemplate_ThenShouldReturnAnError(t *testing.T) {
+	_, err := ParseRoutingKeyTemplate("order.{customer}")
+
+	assert.EqualError(t, err, `unknown routing key variable "customer"`)
+}
+
+func TestGivenNoTemplate_WhenRenderingTheRoutingKey_ThenShouldReturnTheEmptyKey(t *testing.T) {
+	var template *RoutingKeyTemplate
+
+	assert.Equal(t, "", template.Render("OrderCreated", []byte(`{"status":"pending"}`)))
+}
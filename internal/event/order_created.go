@@ -1,15 +1,17 @@
 package event
 
-import "time"
+import "github.com/mvr-garcia/go-clean-arch/pkg/events"
 
 type OrderCreated struct {
+	events.BaseEvent
 	Name    string
 	Payload interface{}
 }
 
 func NewOrderCreated() *OrderCreated {
 	return &OrderCreated{
-		Name: "OrderCreated",
+		BaseEvent: events.NewBaseEvent(),
+		Name:      "OrderCreated",
 	}
 }
 
@@ -25,6 +27,10 @@ func (e *OrderCreated) SetPayload(payload interface{}) {
 	e.Payload = payload
 }
 
-func (e *OrderCreated) GetDateTime() time.Time {
-	return time.Now()
+// Clone returns a new OrderCreated with its own ID and no payload.
+func (e *OrderCreated) Clone() events.EventInterface {
+	return &OrderCreated{
+		BaseEvent: events.NewBaseEvent(),
+		Name:      e.Name,
+	}
 }
@@ -0,0 +1,38 @@
+package event
+
+import "github.com/mvr-garcia/go-clean-arch/pkg/events"
+
+// OrdersArchived is dispatched once per archive run that moved at least one
+// order out of the orders table.
+type OrdersArchived struct {
+	events.BaseEvent
+	Name    string
+	Payload interface{}
+}
+
+func NewOrdersArchived() *OrdersArchived {
+	return &OrdersArchived{
+		BaseEvent: events.NewBaseEvent(),
+		Name:      "OrdersArchived",
+	}
+}
+
+func (e *OrdersArchived) GetName() string {
+	return e.Name
+}
+
+func (e *OrdersArchived) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrdersArchived) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+// Clone returns a new OrdersArchived with its own ID and no payload.
+func (e *OrdersArchived) Clone() events.EventInterface {
+	return &OrdersArchived{
+		BaseEvent: events.NewBaseEvent(),
+		Name:      e.Name,
+	}
+}
@@ -0,0 +1,38 @@
+package event
+
+import "github.com/mvr-garcia/go-clean-arch/pkg/events"
+
+// OrdersStatusChanged is dispatched once per bulk status update, carrying the
+// filter, the new status and the number of orders affected.
+type OrdersStatusChanged struct {
+	events.BaseEvent
+	Name    string
+	Payload interface{}
+}
+
+func NewOrdersStatusChanged() *OrdersStatusChanged {
+	return &OrdersStatusChanged{
+		BaseEvent: events.NewBaseEvent(),
+		Name:      "OrdersStatusChanged",
+	}
+}
+
+func (e *OrdersStatusChanged) GetName() string {
+	return e.Name
+}
+
+func (e *OrdersStatusChanged) GetPayload() interface{} {
+	return e.Payload
+}
+
+func (e *OrdersStatusChanged) SetPayload(payload interface{}) {
+	e.Payload = payload
+}
+
+// Clone returns a new OrdersStatusChanged with its own ID and no payload.
+func (e *OrdersStatusChanged) Clone() events.EventInterface {
+	return &OrdersStatusChanged{
+		BaseEvent: events.NewBaseEvent(),
+		Name:      e.Name,
+	}
+}
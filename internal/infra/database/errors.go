@@ -0,0 +1,94 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+// ErrorCategory groups driver errors by how callers should react to them.
+type ErrorCategory int
+
+const (
+	Unknown ErrorCategory = iota
+	Duplicate
+	Deadlock
+	Connection
+	NotFound
+)
+
+func (c ErrorCategory) String() string {
+	switch c {
+	case Duplicate:
+		return "duplicate"
+	case Deadlock:
+		return "deadlock"
+	case Connection:
+		return "connection"
+	case NotFound:
+		return "not found"
+	default:
+		return "unknown"
+	}
+}
+
+// MySQL server error numbers, see
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	mysqlDuplicateEntry   = 1062
+	mysqlLockWaitTimeout  = 1205
+	mysqlDeadlock         = 1213
+	mysqlServerShutdown   = 1053
+	mysqlTooManyConnected = 1040
+)
+
+// Classify inspects err, including wrapped errors, and returns its category.
+// Deadlocks and lock wait timeouts share a category since both are resolved by
+// retrying the transaction.
+func Classify(err error) ErrorCategory {
+	if err == nil {
+		return Unknown
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlDuplicateEntry:
+			return Duplicate
+		case mysqlDeadlock, mysqlLockWaitTimeout:
+			return Deadlock
+		case mysqlServerShutdown, mysqlTooManyConnected:
+			return Connection
+		}
+		return Unknown
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr) {
+		return Connection
+	}
+	return Unknown
+}
+
+// translateError wraps the driver errors callers outside this package react
+// to with the matching entity sentinel, so they can use errors.Is instead of
+// depending on the driver. The driver error stays in the chain for logs and
+// Classify.
+func translateError(err error) error {
+	switch Classify(err) {
+	case Duplicate:
+		return fmt.Errorf("%w: %w", entity.ErrOrderAlreadyExists, err)
+	case Connection:
+		return fmt.Errorf("%w: %w", entity.ErrStorageUnavailable, err)
+	}
+	return err
+}
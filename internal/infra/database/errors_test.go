@@ -0,0 +1,38 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenRepresentativeDriverErrors_WhenClassify_ThenShouldReturnTheirCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCategory
+	}{
+		{"duplicate entry", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '123' for key 'PRIMARY'"}, Duplicate},
+		{"deadlock", &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}, Deadlock},
+		{"lock wait timeout", &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, Deadlock},
+		{"too many connections", &mysql.MySQLError{Number: 1040, Message: "Too many connections"}, Connection},
+		{"invalid connection", mysql.ErrInvalidConn, Connection},
+		{"bad connection", driver.ErrBadConn, Connection},
+		{"dial error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, Connection},
+		{"no rows", sql.ErrNoRows, NotFound},
+		{"wrapped duplicate", fmt.Errorf("item 2: %w", &mysql.MySQLError{Number: 1062}), Duplicate},
+		{"syntax error", &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}, Unknown},
+		{"other error", errors.New("boom"), Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+}
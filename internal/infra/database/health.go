@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+type HealthStatus struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Err is the cause of an unavailable status. It is logged, never sent.
+	Err error `json:"-"`
+}
+
+func (s HealthStatus) Healthy() bool {
+	return s.Status == HealthStatusOK
+}
+
+// HealthChecker pings the database and caches the outcome for TTL, so frequent
+// probes do not each reach the database. Concurrent probes share one ping.
+type HealthChecker struct {
+	TTL time.Duration
+
+	check func(ctx context.Context) error
+	now   func() time.Time
+
+	mu     sync.Mutex
+	status HealthStatus
+}
+
+func NewHealthChecker(db *sql.DB, ttl time.Duration) *HealthChecker {
+	return &HealthChecker{
+		TTL:   ttl,
+		check: db.PingContext,
+		now:   time.Now,
+	}
+}
+
+// Check returns the cached status while it is younger than TTL and pings the
+// database otherwise.
+func (h *HealthChecker) Check(ctx context.Context) HealthStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.status.CheckedAt.IsZero() && h.now().Sub(h.status.CheckedAt) < h.TTL {
+		return h.status
+	}
+	return h.recheck(ctx)
+}
+
+// Recheck pings the database now and caches the result.
+func (h *HealthChecker) Recheck(ctx context.Context) HealthStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.recheck(ctx)
+}
+
+func (h *HealthChecker) recheck(ctx context.Context) HealthStatus {
+	status := HealthStatus{Status: HealthStatusOK, CheckedAt: h.now().UTC()}
+	if err := h.check(ctx); err != nil {
+		status.Status = HealthStatusUnavailable
+		status.Err = err
+	}
+	h.status = status
+	return status
+}
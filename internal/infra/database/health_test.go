@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenACachedStatus_WhenCheckWithinTheTTL_ThenShouldReuseItUntilForced(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pings := 0
+	var pingErr error
+	checker := &HealthChecker{
+		TTL:   10 * time.Second,
+		check: func(context.Context) error { pings++; return pingErr },
+		now:   func() time.Time { return now },
+	}
+
+	assert.True(t, checker.Check(context.Background()).Healthy())
+	pingErr = errors.New("connection refused")
+	now = now.Add(5 * time.Second)
+	assert.True(t, checker.Check(context.Background()).Healthy())
+	assert.Equal(t, 1, pings)
+
+	status := checker.Recheck(context.Background())
+	assert.Equal(t, HealthStatusUnavailable, status.Status)
+	assert.Equal(t, now, status.CheckedAt)
+	assert.Equal(t, 2, pings)
+
+	pingErr = nil
+	now = now.Add(10 * time.Second)
+	assert.True(t, checker.Check(context.Background()).Healthy())
+	assert.Equal(t, 3, pings)
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+type Migrator interface {
+	Up() error
+	Version() (version uint, dirty bool, err error)
+	Force(version int) error
+}
+
+// DirtySchemaError reports a schema that migration Version left dirty by
+// failing midway. golang-migrate refuses to migrate it again until someone
+// repairs it and marks it clean, so retrying cannot help. Previous is the
+// version to force when the migration is not applied, -1 when it is the first.
+// Err is the failure that left it dirty, when it happened in this run.
+type DirtySchemaError struct {
+	Version  uint
+	Previous int
+	Err      error
+}
+
+func newDirtySchemaError(version uint, err error) error {
+	previous, prevErr := previousVersion(version)
+	if prevErr != nil {
+		return errors.Join(prevErr, err)
+	}
+	return &DirtySchemaError{Version: version, Previous: previous, Err: err}
+}
+
+func (e *DirtySchemaError) Error() string {
+	msg := fmt.Sprintf("database schema is dirty at version %d: repair it by hand, then run `migrate force %d` if migration %d is fully applied or `migrate force %d` if it is not",
+		e.Version, e.Version, e.Version, e.Previous)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *DirtySchemaError) Unwrap() error {
+	return e.Err
+}
+
+// RunMigrations applies all pending migrations, waiting until they finish or
+// ctx is done. If they are still running after warnAfter, a warning is logged
+// once so a migration stuck on a lock is visible before the deadline. When
+// migrations were applied, the schema versions before and after the run are
+// logged. A schema that is dirty before the run, or that a failing migration
+// leaves dirty, is reported as a *DirtySchemaError.
+func RunMigrations(ctx context.Context, migrator Migrator, warnAfter time.Duration, logger *slog.Logger) error {
+	from, dirty, err := currentVersion(migrator)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return newDirtySchemaError(from, nil)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- migrator.Up()
+	}()
+
+	var warn <-chan time.Time
+	if warnAfter > 0 {
+		timer := time.NewTimer(warnAfter)
+		defer timer.Stop()
+		warn = timer.C
+	}
+
+	start := time.Now()
+	for {
+		select {
+		case err := <-done:
+			if errors.Is(err, migrate.ErrNoChange) {
+				return nil
+			}
+			if err != nil {
+				if version, dirty, vErr := currentVersion(migrator); vErr == nil && dirty {
+					return newDirtySchemaError(version, err)
+				}
+				return err
+			}
+			to, _, err := currentVersion(migrator)
+			if err != nil {
+				return err
+			}
+			logger.Info("migrations applied",
+				"from_version", from,
+				"to_version", to,
+				"elapsed", time.Since(start).Round(time.Millisecond),
+			)
+			return nil
+		case <-warn:
+			logger.Warn("migrations are taking longer than expected",
+				"elapsed", time.Since(start).Round(time.Millisecond),
+				"warn_after", warnAfter,
+			)
+			warn = nil
+		case <-ctx.Done():
+			if m, ok := migrator.(*migrate.Migrate); ok {
+				m.GracefulStop <- true
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// ForceDirtySchema marks a dirty schema as clean at the version before the one
+// that failed, so the next RunMigrations retries that migration. It does
+// nothing to a clean schema. Whatever the failed migration left behind is not
+// undone, so it is only meant for development databases.
+func ForceDirtySchema(migrator Migrator, logger *slog.Logger) error {
+	version, dirty, err := currentVersion(migrator)
+	if err != nil || !dirty {
+		return err
+	}
+	previous, err := previousVersion(version)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Force(previous); err != nil {
+		return err
+	}
+	logger.Warn("forced dirty schema clean to retry the failed migration",
+		"dirty_version", version,
+		"forced_version", previous,
+	)
+	return nil
+}
+
+// previousVersion returns the embedded migration before version, or
+// golang-migrate's NilVersion (-1) when version is the first one. Forcing 0
+// instead would record a version no migration has, which Up cannot start from.
+func previousVersion(version uint) (int, error) {
+	source, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+	previous, err := source.Prev(version)
+	if err == nil {
+		return int(previous), nil
+	}
+	if first, firstErr := source.First(); firstErr == nil && first == version {
+		return migratedb.NilVersion, nil
+	}
+	return 0, fmt.Errorf("no embedded migration %d: %w", version, err)
+}
+
+// currentVersion returns the applied schema version and whether it is dirty,
+// or 0 for a database that has never been migrated.
+func currentVersion(migrator Migrator) (uint, bool, error) {
+	version, dirty, err := migrator.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
@@ -0,0 +1,182 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/stretchr/testify/assert"
+)
+
+type MigratorStub struct {
+	Delay time.Duration
+	Err   error
+	// From is the version before Up and To the version it migrates to.
+	From, To uint
+	// Dirty marks From as dirty. A failing Up marks To as dirty.
+	Dirty   bool
+	Forced  []int
+	applied bool
+	upCalls int
+	// zero is set when version 0 was forced, which golang-migrate records
+	// although no migration has it.
+	zero bool
+}
+
+func (m *MigratorStub) Up() error {
+	m.upCalls++
+	time.Sleep(m.Delay)
+	if m.zero {
+		return errors.New("no migration found for version 0")
+	}
+	m.applied = m.Err == nil
+	if m.Err != nil && !errors.Is(m.Err, migrate.ErrNoChange) && m.To > 0 {
+		m.From, m.Dirty = m.To, true
+	}
+	return m.Err
+}
+
+func (m *MigratorStub) Version() (uint, bool, error) {
+	version := m.From
+	if m.applied {
+		version = m.To
+	}
+	if version == 0 && !m.zero {
+		return 0, false, migrate.ErrNilVersion
+	}
+	return version, m.Dirty && !m.applied, nil
+}
+
+func (m *MigratorStub) Force(version int) error {
+	m.Forced = append(m.Forced, version)
+	m.Dirty, m.zero = false, version == 0
+	if version == migratedb.NilVersion {
+		m.From = 0
+		return nil
+	}
+	m.From = uint(version)
+	return nil
+}
+
+func TestGivenASlowMigration_WhenRunMigrations_ThenShouldLogAWarning(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	err := RunMigrations(context.Background(), &MigratorStub{Delay: 50 * time.Millisecond}, 10*time.Millisecond, logger)
+
+	assert.Nil(t, err)
+	assert.Contains(t, logs.String(), "level=WARN")
+	assert.Contains(t, logs.String(), "migrations are taking longer than expected")
+}
+
+func TestGivenPendingMigrations_WhenRunMigrations_ThenShouldLogTheAppliedVersionRange(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	err := RunMigrations(context.Background(), &MigratorStub{From: 2, To: 4}, 0, logger)
+
+	assert.Nil(t, err)
+	assert.Contains(t, logs.String(), "migrations applied")
+	assert.Contains(t, logs.String(), "from_version=2 to_version=4")
+}
+
+func TestGivenAFreshDatabase_WhenRunMigrations_ThenShouldLogFromVersionZero(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	err := RunMigrations(context.Background(), &MigratorStub{To: 4}, 0, logger)
+
+	assert.Nil(t, err)
+	assert.Contains(t, logs.String(), "from_version=0 to_version=4")
+}
+
+func TestGivenAFastMigration_WhenRunMigrations_ThenShouldNotLogAWarning(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	err := RunMigrations(context.Background(), &MigratorStub{Err: migrate.ErrNoChange}, time.Second, logger)
+
+	assert.Nil(t, err)
+	assert.Empty(t, logs.String())
+}
+
+func TestGivenAMigrationPastTheDeadline_WhenRunMigrations_ThenShouldReturnTheContextError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := RunMigrations(ctx, &MigratorStub{Delay: time.Second}, 0, slog.Default())
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestGivenADirtySchema_WhenRunMigrations_ThenShouldRefuseToMigrateAndExplainHowToForce(t *testing.T) {
+	migrator := &MigratorStub{From: 5, To: 7, Dirty: true}
+
+	err := RunMigrations(context.Background(), migrator, 0, slog.Default())
+
+	var dirty *DirtySchemaError
+	assert.True(t, errors.As(err, &dirty))
+	assert.Equal(t, uint(5), dirty.Version)
+	assert.EqualError(t, err, "database schema is dirty at version 5: repair it by hand, then run `migrate force 5` if migration 5 is fully applied or `migrate force 4` if it is not")
+	assert.Equal(t, 0, migrator.upCalls)
+}
+
+func TestGivenAMigrationFailingMidway_WhenRunMigrations_ThenShouldReportTheDirtyVersionAndTheCause(t *testing.T) {
+	migrator := &MigratorStub{From: 4, To: 5, Err: errors.New("Error 1050: Table 'orders_archive' already exists")}
+
+	err := RunMigrations(context.Background(), migrator, 0, slog.Default())
+
+	var dirty *DirtySchemaError
+	assert.True(t, errors.As(err, &dirty))
+	assert.Equal(t, uint(5), dirty.Version)
+	assert.ErrorIs(t, err, migrator.Err)
+	assert.Contains(t, err.Error(), "`migrate force 4` if it is not: Error 1050")
+}
+
+func TestGivenADirtySchema_WhenForceDirtySchema_ThenShouldForceThePreviousVersionSoUpRetriesIt(t *testing.T) {
+	var logs bytes.Buffer
+	migrator := &MigratorStub{From: 5, To: 7, Dirty: true}
+
+	err := ForceDirtySchema(migrator, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{4}, migrator.Forced)
+	assert.Contains(t, logs.String(), "dirty_version=5 forced_version=4")
+	assert.Nil(t, RunMigrations(context.Background(), migrator, 0, slog.Default()))
+	assert.Equal(t, 1, migrator.upCalls)
+}
+
+func TestGivenADirtyFirstMigration_WhenForceDirtySchema_ThenShouldForceNoVersionSoUpRetriesIt(t *testing.T) {
+	migrator := &MigratorStub{From: 1, To: 7, Dirty: true}
+
+	err := RunMigrations(context.Background(), migrator, 0, slog.Default())
+	assert.Contains(t, err.Error(), "`migrate force -1` if it is not")
+
+	assert.Nil(t, ForceDirtySchema(migrator, slog.Default()))
+	assert.Equal(t, []int{migratedb.NilVersion}, migrator.Forced)
+	assert.Nil(t, RunMigrations(context.Background(), migrator, 0, slog.Default()))
+	assert.Equal(t, 1, migrator.upCalls)
+}
+
+func TestGivenADirtyVersionWithNoEmbeddedMigration_WhenForceDirtySchema_ThenShouldFail(t *testing.T) {
+	migrator := &MigratorStub{From: 99, Dirty: true}
+
+	err := ForceDirtySchema(migrator, slog.Default())
+
+	assert.ErrorContains(t, err, "no embedded migration 99")
+	assert.Empty(t, migrator.Forced)
+}
+
+func TestGivenACleanSchema_WhenForceDirtySchema_ThenShouldNotForceAnything(t *testing.T) {
+	migrator := &MigratorStub{From: 5}
+
+	err := ForceDirtySchema(migrator, slog.Default())
+
+	assert.Nil(t, err)
+	assert.Empty(t, migrator.Forced)
+}
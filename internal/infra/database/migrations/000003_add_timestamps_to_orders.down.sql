@@ -0,0 +1,2 @@
+ALTER TABLE orders DROP COLUMN updated_at;
+ALTER TABLE orders DROP COLUMN created_at;
\ No newline at end of file
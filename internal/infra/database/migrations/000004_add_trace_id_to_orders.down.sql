@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN trace_id;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN trace_id varchar(255) NULL;
@@ -0,0 +1,2 @@
+-- MySQL executes the /*! ... */ clause; SQLite, used by the tests, skips it.
+DROP INDEX idx_orders_updated_at /*! ON orders */;
@@ -0,0 +1,9 @@
+-- MySQL executes the /*! ... */ clause; SQLite, used by the tests, skips it.
+CREATE TABLE IF NOT EXISTS order_audit (
+    id INTEGER PRIMARY KEY /*! AUTO_INCREMENT */,
+    order_id varchar(255) NOT NULL,
+    action varchar(20) NOT NULL,
+    status varchar(20) NOT NULL,
+    changed_at DATETIME NOT NULL
+);
+CREATE INDEX idx_order_audit_order_id ON order_audit (order_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS orders_archive (
+    id varchar(255) NOT NULL,
+    price float NOT NULL,
+    tax float NOT NULL,
+    final_price float NOT NULL,
+    status varchar(20) NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    trace_id varchar(255) NULL,
+    archived_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/stretchr/testify/assert"
+
+	// migrate sqlite3 driver
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+)
+
+func TestGivenTheEmbeddedMigrations_WhenRunMigrations_ThenShouldCreateTheOrdersSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	migrator, err := NewMigrator("sqlite3://" + path)
+	assert.Nil(t, err)
+	defer migrator.Close()
+
+	assert.Nil(t, migrator.Up())
+
+	db, err := sql.Open("sqlite3", path)
+	assert.Nil(t, err)
+	defer db.Close()
+	repo := NewOrderRepository(db, DefaultRepositoryConfig())
+	order, err := entity.NewOrder("123", 10.0, 2.0)
+	assert.Nil(t, err)
+	order.TraceID = "trace"
+	assert.Nil(t, order.CalculateFinalPrice())
+	assert.Nil(t, repo.Save(context.Background(), order))
+
+	orders, err := repo.FindAll(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, orders, 1)
+	assert.Equal(t, "trace", orders[0].TraceID)
+}
+
+func TestGivenTheEmbeddedMigrations_WhenMigratingDown_ThenShouldDropTheSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	migrator, err := NewMigrator("sqlite3://" + path)
+	assert.Nil(t, err)
+	defer migrator.Close()
+
+	assert.Nil(t, migrator.Up())
+	assert.Nil(t, migrator.Down())
+
+	db, err := sql.Open("sqlite3", path)
+	assert.Nil(t, err)
+	defer db.Close()
+	var tables int
+	assert.Nil(t, db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'orders'").Scan(&tables))
+	assert.Equal(t, 0, tables)
+}
+
+func TestGivenAPartiallyMigratedDatabase_WhenRunMigrations_ThenShouldLogTheVersionsApplied(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	migrator, err := NewMigrator("sqlite3://" + path)
+	assert.Nil(t, err)
+	defer migrator.Close()
+	assert.Nil(t, migrator.Migrate(2))
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	err = RunMigrations(context.Background(), migrator, 0, logger)
+
+	assert.Nil(t, err)
+	assert.Contains(t, logs.String(), "from_version=2 to_version=7")
+}
@@ -1,59 +1,772 @@
 package database
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/mvr-garcia/go-clean-arch/internal/entity"
 )
 
+// OrderRepository always names the columns it reads and writes, and scans
+// nullable columns through sql.Null types, so a replica keeps working while an
+// additive migration introduces new nullable or defaulted columns.
 type OrderRepository struct {
-	Db *sql.DB
+	Db           *sql.DB
+	QueryTimeout time.Duration
+	// MinIDPrefixLength guards SearchByIDPrefix against near full table scans.
+	MinIDPrefixLength int
+	// CaseSensitiveSearch makes SearchByIDPrefix match case exactly instead of
+	// through the column's case-insensitive collation.
+	CaseSensitiveSearch bool
+	// WindowTotal makes FindPageWithTotal count with COUNT(*) OVER () in the
+	// page query instead of running a separate COUNT(*).
+	WindowTotal bool
+	// RetryReads retries a read once when it fails with a
+	// Connection error, such as a pooled connection left stale by a database
+	// restart. Writes are never retried, as they may have been applied.
+	RetryReads bool
 }
 
-func NewOrderRepository(db *sql.DB) *OrderRepository {
-	return &OrderRepository{Db: db}
+// RepositoryConfig configures the repositories NewOrderRepository returns.
+type RepositoryConfig struct {
+	// QueryTimeout bounds every query whose context has no tighter deadline.
+	QueryTimeout time.Duration
+	// MinIDPrefixLength and CaseSensitiveSearch configure SearchByIDPrefix.
+	MinIDPrefixLength   int
+	CaseSensitiveSearch bool
+	// WindowTotal configures FindPageWithTotal.
+	WindowTotal bool
+	// RetryReads configures whether reads are retried on connection errors.
+	RetryReads bool
 }
 
-func (r *OrderRepository) Save(order *entity.Order) error {
-	stmt, err := r.Db.Prepare("INSERT INTO orders (id, price, tax, final_price) VALUES (?, ?, ?, ?)")
+// DefaultRepositoryConfig returns the configuration used when none of the DB_*,
+// ORDER_SEARCH_* and LIST_TOTAL_WINDOW settings are set.
+func DefaultRepositoryConfig() RepositoryConfig {
+	return RepositoryConfig{QueryTimeout: 30 * time.Second, MinIDPrefixLength: 3, RetryReads: true}
+}
+
+func NewOrderRepository(db *sql.DB, config RepositoryConfig) *OrderRepository {
+	return &OrderRepository{
+		Db:                  db,
+		QueryTimeout:        config.QueryTimeout,
+		MinIDPrefixLength:   config.MinIDPrefixLength,
+		CaseSensitiveSearch: config.CaseSensitiveSearch,
+		WindowTotal:         config.WindowTotal,
+		RetryReads:          config.RetryReads,
+	}
+}
+
+// withTimeout applies QueryTimeout to ctx. A caller deadline that expires
+// sooner is kept as is.
+func (r *OrderRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.QueryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.QueryTimeout)
+}
+
+// Save inserts the order and records its creation. Like every write it reports
+// a duplicate ID as entity.ErrOrderAlreadyExists and an unreachable database
+// as entity.ErrStorageUnavailable.
+func (r *OrderRepository) Save(ctx context.Context, order *entity.Order) error {
+	return translateError(r.save(ctx, order))
+}
+
+func (r *OrderRepository) save(ctx context.Context, order *entity.Order) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO orders (id, price, tax, final_price, status, created_at, updated_at, trace_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", order.ID, order.Price, order.Tax, order.FinalPrice, order.Status, order.CreatedAt, order.UpdatedAt, nullString(order.TraceID))
+	if err != nil {
+		return err
+	}
+	if err := recordChange(ctx, tx, order, entity.OrderChangeCreated); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveOrUpdate inserts the order or, when an order with the same ID already
+// exists, overwrites its mutable fields while keeping the original created_at.
+// The history records which of the two happened.
+func (r *OrderRepository) SaveOrUpdate(ctx context.Context, order *entity.Order) error {
+	return translateError(r.saveOrUpdate(ctx, order))
+}
+
+func (r *OrderRepository) saveOrUpdate(ctx context.Context, order *entity.Order) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// An update without a trace ID keeps the one the order already has.
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO orders (id, price, tax, final_price, status, created_at, updated_at, trace_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE price = VALUES(price), tax = VALUES(tax), final_price = VALUES(final_price), status = VALUES(status), updated_at = VALUES(updated_at), trace_id = COALESCE(VALUES(trace_id), trace_id)",
+		order.ID, order.Price, order.Tax, order.FinalPrice, order.Status, order.CreatedAt, order.UpdatedAt, nullString(order.TraceID),
+	)
 	if err != nil {
 		return err
 	}
-	_, err = stmt.Exec(order.ID, order.Price, order.Tax, order.FinalPrice)
+	// MySQL counts one affected row for an insert and two for an update.
+	affected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
-	return nil
+	action := entity.OrderChangeUpdated
+	if affected == 1 {
+		action = entity.OrderChangeCreated
+	}
+	if err := recordChange(ctx, tx, order, action); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *OrderRepository) FindAll() ([]entity.Order, error) {
-	rows, err := r.Db.Query("SELECT id, price, tax, final_price FROM orders")
+func (r *OrderRepository) SaveBatch(ctx context.Context, orders []*entity.Order) error {
+	return translateError(r.saveBatch(ctx, orders))
+}
+
+func (r *OrderRepository) saveBatch(ctx context.Context, orders []*entity.Order) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO orders (id, price, tax, final_price, status, created_at, updated_at, trace_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, order := range orders {
+		_, err := stmt.ExecContext(ctx, order.ID, order.Price, order.Tax, order.FinalPrice, order.Status, order.CreatedAt, order.UpdatedAt, nullString(order.TraceID))
+		if err != nil {
+			return &entity.BatchItemError{Index: i, Err: err}
+		}
+		if err := recordChange(ctx, tx, order, entity.OrderChangeCreated); err != nil {
+			return &entity.BatchItemError{Index: i, Err: err}
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *OrderRepository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var order entity.Order
+	var traceID sql.NullString
+	err := r.reader().QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders WHERE id = ?", id).
+		Scan(&order.ID, &order.Price, &order.Tax, &order.FinalPrice, &order.Status, &order.CreatedAt, &order.UpdatedAt, &traceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, entity.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+	order.TraceID = traceID.String
+	return &order, nil
+}
+
+func (r *OrderRepository) FindAll(ctx context.Context) ([]entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return findOrders(ctx, r.reader(), newOrderQuery())
+}
+
+func (r *OrderRepository) FindPage(ctx context.Context, limit, offset int) ([]entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return findOrders(ctx, r.reader(), listPageQuery(limit, offset))
+}
+
+// listPageQuery is the page of the order list, oldest first. Every variant of
+// the list pages through it, so their pages line up.
+func listPageQuery(limit, offset int) *orderQuery {
+	return newOrderQuery().OrderBy("created_at", false).OrderBy("id", false).Page(limit, offset)
+}
+
+// FindPageWithTotal returns the page and the number of orders across all
+// pages. With WindowTotal both come from one query, which needs a server
+// passing CheckWindowFunctions; a page past the end has no row to carry the
+// total, so only then is it counted separately. Either way the total counts
+// the rows matching the page's conditions.
+func (r *OrderRepository) FindPageWithTotal(ctx context.Context, limit, offset int) ([]entity.Order, int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := listPageQuery(limit, offset)
+	if r.WindowTotal {
+		orders, total, err := findOrdersWithTotal(ctx, r.reader(), query.WithTotal())
+		if err != nil || len(orders) > 0 || offset == 0 {
+			return orders, total, err
+		}
+	}
+
+	orders, err := findOrders(ctx, r.reader(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := countOrders(ctx, r.reader(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// FindPageAsOf reads the page of FindPage among the orders created before
+// asOf only, so a client paging with the same asOf is not shifted by orders
+// inserted in the meantime.
+func (r *OrderRepository) FindPageAsOf(ctx context.Context, asOf time.Time, limit, offset int) ([]entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return findOrders(ctx, r.reader(), listPageQuery(limit, offset).Where("created_at < ?", asOf))
+}
+
+func (r *OrderRepository) FindByStatus(ctx context.Context, status string, limit int) ([]entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return findOrders(ctx, r.reader(), newOrderQuery().Where("status = ?", status).Page(limit, 0))
+}
+
+func (r *OrderRepository) FindRecent(ctx context.Context, n int) ([]entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := newOrderQuery().OrderBy("created_at", true).OrderBy("id", true).Page(n, 0)
+	return findOrders(ctx, r.reader(), query)
+}
+
+// FindMostExpensive sorts by the stored final_price; ties go to the smallest ID.
+func (r *OrderRepository) FindMostExpensive(ctx context.Context) (*entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := newOrderQuery().OrderBy("final_price", true).OrderBy("id", false).Page(1, 0)
+	orders, err := findOrders(ctx, r.reader(), query)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, entity.ErrOrderNotFound
+	}
+	return &orders[0], nil
+}
+
+// FindUpdatedSince pages through the orders after the cursor, least recently
+// updated first. Each page seeks on (updated_at, id) through the updated_at
+// index instead of skipping an offset, so deep pages cost the same as the
+// first and orders updated meanwhile cannot shift the next page.
+func (r *OrderRepository) FindUpdatedSince(ctx context.Context, after entity.OrderChangeCursor, limit int) ([]entity.Order, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := newOrderQuery()
+	if after.ID == "" {
+		query.Where("updated_at > ?", after.UpdatedAt)
+	} else {
+		query.Where("(updated_at > ? OR (updated_at = ? AND id > ?))", after.UpdatedAt, after.UpdatedAt, after.ID)
+	}
+	query.OrderBy("updated_at", false).OrderBy("id", false).Page(limit, 0)
+	return findOrders(ctx, r.reader(), query)
+}
+
+func (r *OrderRepository) SearchByIDPrefix(ctx context.Context, prefix string, limit int) ([]entity.Order, error) {
+	if len(prefix) < r.MinIDPrefixLength {
+		return nil, entity.ErrIDPrefixTooShort
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// The column's _ci collation already ignores case, so the LIKE can range
+	// scan the primary key; the case-sensitive check only filters that range.
+	query := newOrderQuery().Where("id LIKE ? ESCAPE '!'", likePrefix(prefix))
+	if r.CaseSensitiveSearch {
+		if r.isMySQL() {
+			query.Where("id LIKE BINARY ? ESCAPE '!'", likePrefix(prefix))
+		} else {
+			query.Where("SUBSTR(id, 1, ?) = ?", utf8.RuneCountInString(prefix), prefix)
+		}
+	}
+	return findOrders(ctx, r.reader(), query.OrderBy("id", false).Page(limit, 0))
+}
+
+func (r *OrderRepository) UpdateStatusWhere(ctx context.Context, filter entity.OrderStatusFilter, status string, updatedAt time.Time) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	filterQuery := newOrderQuery().Where("status = ?", filter.Status)
+	if !filter.CreatedBefore.IsZero() {
+		filterQuery.Where("created_at < ?", filter.CreatedBefore)
+	}
+	where, whereArgs := filterQuery.WhereClause()
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// The matching orders are recorded before the update, while their old
+	// status still identifies them.
+	_, err = tx.ExecContext(ctx, "INSERT INTO order_audit (order_id, action, status, changed_at) SELECT id, ?, ?, ? FROM orders"+where,
+		append([]interface{}{entity.OrderChangeStatusChanged, status, updatedAt}, whereArgs...)...)
+	if err != nil {
+		return 0, err
+	}
+	result, err := tx.ExecContext(ctx, "UPDATE orders SET status = ?, updated_at = ?"+where,
+		append([]interface{}{status, updatedAt}, whereArgs...)...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), tx.Commit()
+}
+
+// FindHistory returns the audit trail of the order, oldest change first.
+func (r *OrderRepository) FindHistory(ctx context.Context, id string) ([]entity.OrderChange, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.reader().QueryContext(ctx, "SELECT order_id, action, status, changed_at FROM order_audit WHERE order_id = ? ORDER BY changed_at, id", id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var orders []entity.Order
+	var changes []entity.OrderChange
 	for rows.Next() {
-		var order entity.Order
-		err := rows.Scan(&order.ID, &order.Price, &order.Tax, &order.FinalPrice)
-		if err != nil {
+		var change entity.OrderChange
+		if err := rows.Scan(&change.OrderID, &change.Action, &change.Status, &change.ChangedAt); err != nil {
 			return nil, err
 		}
-		orders = append(orders, order)
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Version reads the latest updated_at through the updated_at index rather than
+// MAX(), which some drivers return untyped.
+func (r *OrderRepository) Version(ctx context.Context) (entity.OrderSetVersion, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// One statement, so the count and the latest update describe the same
+	// state of the table. No row means no orders, the zero version.
+	var version entity.OrderSetVersion
+	err := r.reader().QueryRowContext(ctx, "SELECT (SELECT count(*) FROM orders), updated_at FROM orders ORDER BY updated_at DESC LIMIT 1").
+		Scan(&version.Count, &version.LastUpdatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return entity.OrderSetVersion{}, err
+	}
+	return version, nil
+}
+
+func (r *OrderRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.reader().QueryContext(ctx, "SELECT status, count(*) FROM orders GROUP BY status")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return orders, nil
+	return counts, nil
 }
 
-func (r *OrderRepository) GetTotal() (int, error) {
+// Export writes every order to w as newline-delimited JSON, one row at a time,
+// so memory use does not grow with the size of the table. Only fields are
+// selected and written, in the order given; an empty trace_id is left out and
+// timestamps are formatted in UTC with timeLayout. Being a long-running
+// stream, it is only bounded by the caller's context, not by QueryTimeout.
+func (r *OrderRepository) Export(ctx context.Context, w io.Writer, timeLayout string, fields ...string) error {
+	if len(fields) == 0 {
+		fields = entity.OrderExportFields
+	}
+	if err := entity.ValidateExportFields(fields); err != nil {
+		return err
+	}
+
+	rows, err := r.reader().QueryContext(ctx, "SELECT "+strings.Join(fields, ", ")+" FROM orders ORDER BY created_at, id")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	dest := make([]interface{}, len(fields))
+	for i, field := range fields {
+		dest[i] = exportDest(field)
+	}
+	var line bytes.Buffer
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		line.Reset()
+		line.WriteByte('{')
+		for i, field := range fields {
+			var value interface{}
+			switch v := dest[i].(type) {
+			case *sql.NullString:
+				if v.String == "" {
+					continue
+				}
+				value = v.String
+			case *string:
+				value = *v
+			case *float64:
+				value = *v
+			case *time.Time:
+				value = v.UTC().Format(timeLayout)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if line.Len() > 1 {
+				line.WriteByte(',')
+			}
+			line.WriteString(`"` + field + `":`)
+			line.Write(encoded)
+		}
+		line.WriteString("}\n")
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// exportDest returns a scan destination of the column's type.
+func exportDest(field string) interface{} {
+	switch field {
+	case "price", "tax", "final_price":
+		return new(float64)
+	case "created_at", "updated_at":
+		return new(time.Time)
+	case "trace_id":
+		return new(sql.NullString)
+	default:
+		return new(string)
+	}
+}
+
+// Orders reads every order as the caller iterates, holding only the current
+// row. Like Export, it is only bounded by the caller's context, not by
+// QueryTimeout, and the rows are closed when the caller stops early.
+func (r *OrderRepository) Orders(ctx context.Context) iter.Seq2[entity.Order, error] {
+	return func(yield func(entity.Order, error) bool) {
+		statement, args, err := newOrderQuery().OrderBy("created_at", false).OrderBy("id", false).Select()
+		if err != nil {
+			yield(entity.Order{}, err)
+			return
+		}
+		rows, err := r.reader().QueryContext(ctx, statement, args...)
+		if err != nil {
+			yield(entity.Order{}, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			order, err := scanOrder(rows)
+			if err != nil {
+				yield(entity.Order{}, err)
+				return
+			}
+			if !yield(order, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(entity.Order{}, err)
+		}
+	}
+}
+
+// TotalRevenue sums final_price over the orders that were not cancelled.
+func (r *OrderRepository) TotalRevenue(ctx context.Context) (float64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var total float64
+	err := r.reader().QueryRowContext(ctx, "SELECT COALESCE(SUM(final_price), 0) FROM orders WHERE status <> ?", entity.OrderStatusCancelled).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+	return entity.RoundPrice(total), nil
+}
+
+// ArchiveBefore copies up to limit of the oldest orders created before cutoff
+// to orders_archive and deletes them from orders, in one transaction. The
+// batch is locked when it is picked, so a concurrent update either lands
+// before the copy or waits for the delete, and the copy and delete are one
+// statement each.
+func (r *OrderRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int, archivedAt time.Time) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	ids, err := lockIDs(ctx, tx, "SELECT id FROM orders WHERE created_at < ? ORDER BY created_at, id LIMIT ?"+r.forUpdate(), cutoff, limit)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	in := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	_, err = tx.ExecContext(ctx, "INSERT INTO orders_archive ("+orderColumns+", archived_at) SELECT "+orderColumns+", ? FROM orders WHERE id IN ("+in+")", append([]interface{}{archivedAt}, ids...)...)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM orders WHERE id IN ("+in+")", ids...); err != nil {
+		return 0, err
+	}
+	return len(ids), tx.Commit()
+}
+
+// lockIDs returns the ids selected by query, as args ready for an IN list.
+func lockIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]interface{}, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []interface{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// forUpdate returns the locking clause for a SELECT picking rows to change.
+// SQLite has none and needs none, as it serializes write transactions.
+func (r *OrderRepository) forUpdate() string {
+	if r.isMySQL() {
+		return " FOR UPDATE"
+	}
+	return ""
+}
+
+// isMySQL reports whether the repository runs on MySQL rather than the SQLite
+// the tests use, for the few clauses they spell differently.
+func (r *OrderRepository) isMySQL() bool {
+	_, ok := r.Db.Driver().(*mysql.MySQLDriver)
+	return ok
+}
+
+func (r *OrderRepository) GetTotal(ctx context.Context) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var total int
-	err := r.Db.QueryRow("Select count(*) from orders").Scan(&total)
+	err := r.reader().QueryRowContext(ctx, "Select count(*) from orders").Scan(&total)
 	if err != nil {
 		return 0, err
 	}
 	return total, nil
 }
+
+// reader is what the non-transactional reads query through, retrying them
+// when RetryReads is set.
+func (r *OrderRepository) reader() retryingReader {
+	return retryingReader{db: r.Db, retry: r.RetryReads}
+}
+
+// retryingReader runs read queries on db and, when retry is set, runs a query
+// that failed with a Connection error once more. The pool discards connections
+// the driver reports as bad, so the second attempt gets another connection.
+type retryingReader struct {
+	db    *sql.DB
+	retry bool
+}
+
+func (q retryingReader) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if q.shouldRetry(ctx, err) {
+		return q.db.QueryContext(ctx, query, args...)
+	}
+	return rows, err
+}
+
+func (q retryingReader) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	row := q.db.QueryRowContext(ctx, query, args...)
+	if q.shouldRetry(ctx, row.Err()) {
+		return q.db.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+func (q retryingReader) shouldRetry(ctx context.Context, err error) bool {
+	return q.retry && ctx.Err() == nil && Classify(err) == Connection
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func findOrders(ctx context.Context, db queryer, query *orderQuery) ([]entity.Order, error) {
+	statement, args, err := query.Select()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
+// countOrders counts the rows matching the conditions of query, ignoring its
+// sort and page.
+func countOrders(ctx context.Context, db queryer, query *orderQuery) (int, error) {
+	where, args := query.WhereClause()
+	rows, err := db.QueryContext(ctx, "SELECT COUNT(*) FROM orders"+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var total int
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, rows.Err()
+}
+
+// findOrdersWithTotal runs a query built WithTotal and returns the total read
+// from its last column.
+func findOrdersWithTotal(ctx context.Context, db queryer, query *orderQuery) ([]entity.Order, int, error) {
+	statement, args, err := query.Select()
+	if err != nil {
+		return nil, 0, err
+	}
+	rows, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var orders []entity.Order
+	var total int
+	for rows.Next() {
+		order, err := scanOrder(rows, &total)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+func scanOrders(rows *sql.Rows) ([]entity.Order, error) {
+	var orders []entity.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// scanOrder scans orderColumns and then any extra columns into extra.
+func scanOrder(rows *sql.Rows, extra ...interface{}) (entity.Order, error) {
+	var order entity.Order
+	var traceID sql.NullString
+	dest := append([]interface{}{&order.ID, &order.Price, &order.Tax, &order.FinalPrice, &order.Status, &order.CreatedAt, &order.UpdatedAt, &traceID}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return entity.Order{}, err
+	}
+	order.TraceID = traceID.String
+	return order, nil
+}
+
+// recordChange appends action to the order's audit trail within tx, so the
+// entry is only kept if the change itself is committed.
+func recordChange(ctx context.Context, tx *sql.Tx, order *entity.Order, action string) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO order_audit (order_id, action, status, changed_at) VALUES (?, ?, ?, ?)", order.ID, action, order.Status, order.UpdatedAt)
+	return err
+}
+
+// likePrefix escapes LIKE wildcards in prefix, using '!' as the escape
+// character, and appends the trailing wildcard.
+func likePrefix(prefix string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(prefix) + "%"
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
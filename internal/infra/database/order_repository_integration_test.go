@@ -0,0 +1,190 @@
+//go:build integration
+
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/database"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/infratest"
+	"github.com/stretchr/testify/suite"
+)
+
+// OrderRepositoryIntegrationSuite runs the repository against a migrated
+// MySQL, for the behaviour sqlite cannot stand in for: the real schema,
+// MySQL's error codes and its transactions.
+type OrderRepositoryIntegrationSuite struct {
+	suite.Suite
+	Db *sql.DB
+}
+
+func (suite *OrderRepositoryIntegrationSuite) SetupSuite() {
+	suite.Db = infratest.MySQL(suite.T())
+}
+
+func (suite *OrderRepositoryIntegrationSuite) SetupTest() {
+	for _, table := range []string{"orders", "order_audit", "orders_archive"} {
+		_, err := suite.Db.Exec("DELETE FROM " + table)
+		suite.NoError(err)
+	}
+}
+
+func TestOrderRepositoryIntegrationSuite(t *testing.T) {
+	suite.Run(t, new(OrderRepositoryIntegrationSuite))
+}
+
+func newIntegrationOrder(id string) *entity.Order {
+	order, _ := entity.NewOrder(id, 10.0, 2.0)
+	order.CalculateFinalPrice()
+	order.TraceID = "host/abc-000001"
+	return order
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenASavedOrder_WhenFindByID_ThenShouldReadItBackFromTheMigratedSchema() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	order := newIntegrationOrder("123")
+	suite.NoError(repo.Save(context.Background(), order))
+
+	found, err := repo.FindByID(context.Background(), "123")
+
+	suite.NoError(err)
+	suite.Equal(order.ID, found.ID)
+	suite.Equal(12.0, found.FinalPrice)
+	suite.Equal(entity.OrderStatusPending, found.Status)
+	suite.Equal(order.TraceID, found.TraceID)
+	suite.True(order.CreatedAt.Equal(found.CreatedAt), "created_at %s, want %s", found.CreatedAt, order.CreatedAt)
+
+	history, err := repo.FindHistory(context.Background(), "123")
+	suite.NoError(err)
+	suite.Len(history, 1)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenADuplicateID_WhenSave_ThenMySQLShouldReportItAsADuplicate() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	suite.NoError(repo.Save(context.Background(), newIntegrationOrder("123")))
+
+	err := repo.Save(context.Background(), newIntegrationOrder("123"))
+
+	suite.Equal(database.Duplicate, database.Classify(err))
+	suite.ErrorIs(err, entity.ErrOrderAlreadyExists)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenABatchWithADuplicate_WhenSaveBatch_ThenShouldRollBackEveryOrder() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	suite.NoError(repo.Save(context.Background(), newIntegrationOrder("2")))
+
+	err := repo.SaveBatch(context.Background(), []*entity.Order{newIntegrationOrder("1"), newIntegrationOrder("2")})
+
+	var itemErr *entity.BatchItemError
+	suite.True(errors.As(err, &itemErr))
+	suite.Equal(1, itemErr.Index)
+	_, err = repo.FindByID(context.Background(), "1")
+	suite.ErrorIs(err, entity.ErrOrderNotFound)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenOldOrders_WhenArchiveBefore_ThenShouldMoveThemToTheArchiveTable() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	old := newIntegrationOrder("old")
+	old.CreatedAt = old.CreatedAt.Add(-48 * time.Hour)
+	suite.NoError(repo.Save(context.Background(), old))
+	suite.NoError(repo.Save(context.Background(), newIntegrationOrder("new")))
+
+	archived, err := repo.ArchiveBefore(context.Background(), time.Now().Add(-24*time.Hour), 10, time.Now().UTC())
+
+	suite.NoError(err)
+	suite.Equal(1, archived)
+	var count int
+	suite.NoError(suite.Db.QueryRow("SELECT COUNT(*) FROM orders_archive WHERE id = 'old'").Scan(&count))
+	suite.Equal(1, count)
+	_, err = repo.FindByID(context.Background(), "old")
+	suite.ErrorIs(err, entity.ErrOrderNotFound)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenOrders_WhenFindPageWithTotal_ThenShouldCountEveryPage() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	for _, id := range []string{"1", "2", "3"} {
+		suite.NoError(repo.Save(context.Background(), newIntegrationOrder(id)))
+	}
+
+	orders, total, err := repo.FindPageWithTotal(context.Background(), 2, 0)
+
+	suite.NoError(err)
+	suite.Len(orders, 2)
+	suite.Equal(3, total)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenTheTargetMySQL_WhenCheckWindowFunctions_ThenShouldMatchWhetherTheWindowTotalRuns() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	repo.WindowTotal = true
+	suite.NoError(repo.Save(context.Background(), newIntegrationOrder("1")))
+
+	checkErr := database.CheckWindowFunctions(context.Background(), suite.Db)
+	_, total, err := repo.FindPageWithTotal(context.Background(), 10, 0)
+
+	if checkErr != nil {
+		suite.Error(err)
+		return
+	}
+	suite.NoError(err)
+	suite.Equal(1, total)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenMixedCaseIDs_WhenSearchByIDPrefix_ThenShouldFollowTheCaseSetting() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	for _, id := range []string{"ABC-1", "abc-2"} {
+		suite.NoError(repo.Save(context.Background(), newIntegrationOrder(id)))
+	}
+
+	orders, err := repo.SearchByIDPrefix(context.Background(), "abc", 10)
+	suite.NoError(err)
+	suite.Len(orders, 2)
+
+	repo.CaseSensitiveSearch = true
+	orders, err = repo.SearchByIDPrefix(context.Background(), "abc", 10)
+	suite.NoError(err)
+	suite.Len(orders, 1)
+	suite.Equal("abc-2", orders[0].ID)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenANewThenAnExistingOrder_WhenSaveOrUpdate_ThenTheHistoryShouldTellInsertFromUpdate() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	order := newIntegrationOrder("123")
+	suite.NoError(repo.SaveOrUpdate(context.Background(), order))
+	order.Price = 20
+	order.UpdatedAt = order.UpdatedAt.Add(time.Second)
+	suite.NoError(repo.SaveOrUpdate(context.Background(), order))
+
+	history, err := repo.FindHistory(context.Background(), "123")
+
+	suite.NoError(err)
+	suite.Len(history, 2)
+	suite.Equal(entity.OrderChangeCreated, history[0].Action)
+	suite.Equal(entity.OrderChangeUpdated, history[1].Action)
+}
+
+func (suite *OrderRepositoryIntegrationSuite) TestGivenATracedOrder_WhenSaveOrUpdate_ThenShouldKeepTheTraceIDUnlessANewOneIsGiven() {
+	repo := database.NewOrderRepository(suite.Db, database.DefaultRepositoryConfig())
+	order := newIntegrationOrder("123")
+	order.TraceID = "host/abc-000001"
+	suite.NoError(repo.SaveOrUpdate(context.Background(), order))
+	order.TraceID = ""
+	order.UpdatedAt = order.UpdatedAt.Add(time.Second)
+	suite.NoError(repo.SaveOrUpdate(context.Background(), order))
+
+	stored, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	suite.Equal("host/abc-000001", stored.TraceID)
+
+	order.TraceID = "host/abc-000002"
+	order.UpdatedAt = order.UpdatedAt.Add(time.Second)
+	suite.NoError(repo.SaveOrUpdate(context.Background(), order))
+
+	stored, err = repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	suite.Equal("host/abc-000002", stored.TraceID)
+}
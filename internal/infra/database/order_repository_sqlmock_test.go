@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+const upsertOrderQuery = "INSERT INTO orders (id, price, tax, final_price, status, created_at, updated_at, trace_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?) " +
+	"ON DUPLICATE KEY UPDATE price = VALUES(price), tax = VALUES(tax), final_price = VALUES(final_price), status = VALUES(status), updated_at = VALUES(updated_at), trace_id = COALESCE(VALUES(trace_id), trace_id)"
+
+const insertChangeQuery = "INSERT INTO order_audit (order_id, action, status, changed_at) VALUES (?, ?, ?, ?)"
+
+func TestGivenANewOrder_WhenSaveOrUpdate_ThenShouldInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	order, err := entity.NewOrder("123", 10.0, 2.0)
+	assert.Nil(t, err)
+	assert.Nil(t, order.CalculateFinalPrice())
+	order.TraceID = "host/abc-000001"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(upsertOrderQuery)).
+		WithArgs(order.ID, order.Price, order.Tax, order.FinalPrice, order.Status, order.CreatedAt, order.UpdatedAt, nullString(order.TraceID)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(insertChangeQuery)).
+		WithArgs(order.ID, entity.OrderChangeCreated, order.Status, order.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := NewOrderRepository(db, DefaultRepositoryConfig())
+	assert.Nil(t, repo.SaveOrUpdate(context.Background(), order))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGivenAnExistingOrderID_WhenSaveOrUpdate_ThenShouldUpdateInsteadOfFailing(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	order, err := entity.NewOrder("123", 20.0, 4.0)
+	assert.Nil(t, err)
+	assert.Nil(t, order.CalculateFinalPrice())
+
+	// MySQL reports two affected rows when ON DUPLICATE KEY UPDATE changes an existing row.
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(upsertOrderQuery)).
+		WithArgs(order.ID, 20.0, 4.0, 24.0, order.Status, order.CreatedAt, order.UpdatedAt, nullString("")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(insertChangeQuery)).
+		WithArgs(order.ID, entity.OrderChangeUpdated, order.Status, order.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	repo := NewOrderRepository(db, DefaultRepositoryConfig())
+	assert.Nil(t, repo.SaveOrUpdate(context.Background(), order))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGivenAQuerySlowerThanTheQueryTimeout_WhenFindAll_ThenShouldBeCancelled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM orders").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewOrderRepository(db, DefaultRepositoryConfig())
+	repo.QueryTimeout = 20 * time.Millisecond
+	start := time.Now()
+	_, err = repo.FindAll(context.Background())
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestGivenACallerDeadlineShorterThanTheQueryTimeout_WhenFindAll_ThenShouldKeepTheCallerDeadline(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM orders").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewOrderRepository(db, DefaultRepositoryConfig())
+	repo.QueryTimeout = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = repo.FindAll(ctx)
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestGivenWindowTotal_WhenFindPageWithTotal_ThenShouldReadItemsAndTotalInOneQuery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + orderColumns + ", COUNT(*) OVER () AS total FROM orders ORDER BY created_at, id LIMIT ?")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "price", "tax", "final_price", "status", "created_at", "updated_at", "trace_id", "total"}).
+			AddRow("a", 10.0, 2.0, 12.0, entity.OrderStatusPending, createdAt, createdAt, nil, 3))
+
+	repo := NewOrderRepository(db, DefaultRepositoryConfig())
+	repo.WindowTotal = true
+	orders, total, err := repo.FindPageWithTotal(context.Background(), 1, 0)
+
+	assert.Nil(t, err)
+	assert.Len(t, orders, 1)
+	assert.Equal(t, "a", orders[0].ID)
+	assert.Equal(t, 3, total)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGivenAStaleConnection_WhenFindPage_ThenShouldRetryOnce(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "price", "tax", "final_price", "status", "created_at", "updated_at", "trace_id"}
+
+	mock.ExpectQuery("SELECT (.+) FROM orders").WillReturnError(mysql.ErrInvalidConn)
+	mock.ExpectQuery("SELECT (.+) FROM orders").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("a", 10.0, 2.0, 12.0, entity.OrderStatusPending, createdAt, createdAt, nil))
+
+	orders, err := NewOrderRepository(db, DefaultRepositoryConfig()).FindPage(context.Background(), 10, 0)
+
+	assert.Nil(t, err)
+	assert.Len(t, orders, 1)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGivenRetryReadsDisabled_WhenFindByIDHitsAStaleConnection_ThenShouldFail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM orders WHERE id = ?").WillReturnError(mysql.ErrInvalidConn)
+
+	repo := NewOrderRepository(db, DefaultRepositoryConfig())
+	repo.RetryReads = false
+	_, err = repo.FindByID(context.Background(), "a")
+
+	assert.Equal(t, Connection, Classify(err))
+	assert.ErrorIs(t, err, entity.ErrStorageUnavailable)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGivenADuplicateID_WhenSave_ThenShouldReturnTheEntitySentinel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '123' for key 'PRIMARY'"})
+	mock.ExpectRollback()
+
+	order, err := entity.NewOrder("123", 10.0, 2.0)
+	assert.Nil(t, err)
+	err = NewOrderRepository(db, DefaultRepositoryConfig()).Save(context.Background(), order)
+
+	assert.ErrorIs(t, err, entity.ErrOrderAlreadyExists)
+	assert.NotErrorIs(t, err, entity.ErrDomain)
+	assert.Equal(t, Duplicate, Classify(err))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGivenOldOrders_WhenArchiveBefore_ThenShouldCopyAndDeleteTheLockedBatchInOneStatementEach(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	defer db.Close()
+	cutoff := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	archivedAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM orders WHERE created_at < ? ORDER BY created_at, id LIMIT ?")).
+		WithArgs(cutoff, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1").AddRow("2"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO orders_archive ("+orderColumns+", archived_at) SELECT "+orderColumns+", ? FROM orders WHERE id IN (?, ?)")).
+		WithArgs(archivedAt, "1", "2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM orders WHERE id IN (?, ?)")).
+		WithArgs("1", "2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	archived, err := NewOrderRepository(db, DefaultRepositoryConfig()).ArchiveBefore(context.Background(), cutoff, 2, archivedAt)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, archived)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
@@ -1,10 +1,18 @@
 package database
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
 	"github.com/stretchr/testify/suite"
 
 	// sqlite3
@@ -16,10 +24,13 @@ type OrderRepositoryTestSuite struct {
 	Db *sql.DB
 }
 
-func (suite *OrderRepositoryTestSuite) SetupSuite() {
+func (suite *OrderRepositoryTestSuite) SetupTest() {
 	db, err := sql.Open("sqlite3", ":memory:")
 	suite.NoError(err)
-	db.Exec("CREATE TABLE orders (id varchar(255) NOT NULL, price float NOT NULL, tax float NOT NULL, final_price float NOT NULL, PRIMARY KEY (id))")
+	db.SetMaxOpenConns(1)
+	db.Exec("CREATE TABLE orders (id varchar(255) NOT NULL, price float NOT NULL, tax float NOT NULL, final_price float NOT NULL, status varchar(20) NOT NULL DEFAULT 'pending', created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, updated_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP, trace_id varchar(255) NULL, PRIMARY KEY (id))")
+	db.Exec("CREATE TABLE order_audit (id INTEGER PRIMARY KEY, order_id varchar(255) NOT NULL, action varchar(20) NOT NULL, status varchar(20) NOT NULL, changed_at datetime NOT NULL)")
+	db.Exec("CREATE TABLE orders_archive (id varchar(255) NOT NULL, price float NOT NULL, tax float NOT NULL, final_price float NOT NULL, status varchar(20) NOT NULL, created_at datetime NOT NULL, updated_at datetime NOT NULL, trace_id varchar(255) NULL, archived_at datetime NOT NULL, PRIMARY KEY (id))")
 	suite.Db = db
 }
 
@@ -35,8 +46,8 @@ func (suite *OrderRepositoryTestSuite) TestGivenAnOrder_WhenSave_ThenShouldSaveO
 	order, err := entity.NewOrder("123", 10.0, 2.0)
 	suite.NoError(err)
 	suite.NoError(order.CalculateFinalPrice())
-	repo := NewOrderRepository(suite.Db)
-	err = repo.Save(order)
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	err = repo.Save(context.Background(), order)
 	suite.NoError(err)
 
 	var orderResult entity.Order
@@ -49,3 +60,560 @@ func (suite *OrderRepositoryTestSuite) TestGivenAnOrder_WhenSave_ThenShouldSaveO
 	suite.Equal(order.Tax, orderResult.Tax)
 	suite.Equal(order.FinalPrice, orderResult.FinalPrice)
 }
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrdersWithStatuses_WhenCountByStatus_ThenShouldGroupCounts() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	for i, status := range []string{entity.OrderStatusPending, entity.OrderStatusPending, entity.OrderStatusShipped} {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.Status = status
+		suite.NoError(order.CalculateFinalPrice())
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	counts, err := repo.CountByStatus(context.Background())
+	suite.NoError(err)
+	suite.Equal(map[string]int{entity.OrderStatusPending: 2, entity.OrderStatusShipped: 1}, counts)
+
+	orders, err := repo.FindByStatus(context.Background(), entity.OrderStatusPending, 1)
+	suite.NoError(err)
+	suite.Len(orders, 1)
+	suite.Equal(entity.OrderStatusPending, orders[0].Status)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrders_WhenVersion_ThenShouldCountThemAndReturnTheLatestUpdate() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	version, err := repo.Version(context.Background())
+	suite.NoError(err)
+	suite.Equal(entity.OrderSetVersion{}, version)
+
+	latest := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, updatedAt := range []time.Time{latest.Add(-time.Hour), latest} {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.UpdatedAt = updatedAt
+		suite.NoError(order.CalculateFinalPrice())
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	version, err = repo.Version(context.Background())
+	suite.NoError(err)
+	suite.Equal(2, version.Count)
+	suite.True(latest.Equal(version.LastUpdatedAt))
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAnOrder_WhenFindAll_ThenShouldReturnTimestamps() {
+	order, err := entity.NewOrder("123", 10.0, 2.0)
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	suite.NoError(repo.Save(context.Background(), order))
+
+	orders, err := repo.FindAll(context.Background())
+	suite.NoError(err)
+	suite.Len(orders, 1)
+	suite.True(order.CreatedAt.Equal(orders[0].CreatedAt))
+	suite.True(order.UpdatedAt.Equal(orders[0].UpdatedAt))
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenSeededOrders_WhenExport_ThenShouldWriteOneJSONObjectPerLine() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	for i := 0; i < 3; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0+float64(i), 2.0)
+		suite.NoError(err)
+		suite.NoError(order.CalculateFinalPrice())
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	var out bytes.Buffer
+	suite.NoError(repo.Export(context.Background(), &out, time.RFC3339))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	suite.Len(lines, 3)
+	for i, line := range lines {
+		var exported map[string]interface{}
+		suite.NoError(json.Unmarshal([]byte(line), &exported))
+		suite.Equal(fmt.Sprintf("order-%d", i), exported["id"])
+		suite.Equal(10.0+float64(i), exported["price"])
+		suite.Equal(12.0+float64(i), exported["final_price"])
+		suite.Equal(entity.OrderStatusPending, exported["status"])
+	}
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenATimeLayout_WhenExport_ThenShouldFormatTimestampsWithItInUTC() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	order, err := entity.NewOrder("order-0", 10.0, 2.0)
+	suite.NoError(err)
+	order.CreatedAt = time.Date(2024, 5, 1, 9, 30, 0, 123, time.FixedZone("BRT", -3*60*60))
+	suite.NoError(order.CalculateFinalPrice())
+	suite.NoError(repo.Save(context.Background(), order))
+
+	var out bytes.Buffer
+	suite.NoError(repo.Export(context.Background(), &out, "2006-01-02 15:04:05", "id", "created_at"))
+
+	suite.Equal(`{"id":"order-0","created_at":"2024-05-01 12:30:00"}`+"\n", out.String())
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAFieldList_WhenExport_ThenShouldOnlyWriteThoseFields() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	order, err := entity.NewOrder("order-0", 10.0, 2.0)
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	suite.NoError(repo.Save(context.Background(), order))
+
+	var out bytes.Buffer
+	suite.NoError(repo.Export(context.Background(), &out, time.RFC3339, "final_price", "id"))
+
+	suite.Equal(`{"final_price":12,"id":"order-0"}`+"\n", out.String())
+	suite.ErrorIs(repo.Export(context.Background(), &out, time.RFC3339, "price; DROP TABLE orders"), entity.ErrInvalidExportField)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAnOrderWithATraceID_WhenSave_ThenShouldPersistTheTraceID() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	traced, err := entity.NewOrder("traced", 10.0, 2.0)
+	suite.NoError(err)
+	traced.TraceID = "host/abc-000001"
+	suite.NoError(repo.Save(context.Background(), traced))
+	untraced, err := entity.NewOrder("untraced", 10.0, 2.0)
+	suite.NoError(err)
+	suite.NoError(repo.Save(context.Background(), untraced))
+
+	var traceID sql.NullString
+	suite.NoError(suite.Db.QueryRow("SELECT trace_id FROM orders WHERE id = ?", "untraced").Scan(&traceID))
+	suite.False(traceID.Valid)
+
+	orders, err := repo.FindAll(context.Background())
+	suite.NoError(err)
+	traceIDs := map[string]string{}
+	for _, order := range orders {
+		traceIDs[order.ID] = order.TraceID
+	}
+	suite.Equal(map[string]string{"traced": "host/abc-000001", "untraced": ""}, traceIDs)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrdersCreatedOverTime_WhenFindRecent_ThenShouldReturnTheNewestFirst() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.CreatedAt = start.Add(time.Duration(i) * time.Hour)
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	orders, err := repo.FindRecent(context.Background(), 3)
+	suite.NoError(err)
+	suite.Len(orders, 3)
+	suite.Equal("order-4", orders[0].ID)
+	suite.Equal("order-3", orders[1].ID)
+	suite.Equal("order-2", orders[2].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenSeededOrders_WhenFindPage_ThenShouldReturnThatSliceInCreationOrder() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.CreatedAt = start.Add(time.Duration(i) * time.Hour)
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	orders, err := repo.FindPage(context.Background(), 2, 1)
+	suite.NoError(err)
+	suite.Len(orders, 2)
+	suite.Equal("order-1", orders[0].ID)
+	suite.Equal("order-2", orders[1].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenSeededOrders_WhenIteratingOrders_ThenShouldYieldThemInCreationOrderUntilStopped() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.CreatedAt = start.Add(time.Duration(2-i) * time.Hour)
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	var ids []string
+	for order, err := range repo.Orders(context.Background()) {
+		suite.NoError(err)
+		ids = append(ids, order.ID)
+	}
+	suite.Equal([]string{"order-2", "order-1", "order-0"}, ids)
+
+	ids = nil
+	for order := range repo.Orders(context.Background()) {
+		ids = append(ids, order.ID)
+		break
+	}
+	suite.Equal([]string{"order-2"}, ids)
+	// The early stop closed the rows, freeing the only connection.
+	_, err := repo.FindByID(context.Background(), "order-0")
+	suite.NoError(err)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrdersInsertedWhilePaging_WhenFindPageAsOf_ThenShouldKeepThePagesStable() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.CreatedAt = start.Add(time.Duration(i) * time.Hour)
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+	asOf := start.Add(3 * time.Hour)
+
+	first, err := repo.FindPageAsOf(context.Background(), asOf, 2, 0)
+	suite.NoError(err)
+	suite.Len(first, 2)
+
+	inserted, err := entity.NewOrder("order-new", 10.0, 2.0)
+	suite.NoError(err)
+	inserted.CreatedAt = asOf
+	suite.NoError(repo.Save(context.Background(), inserted))
+
+	second, err := repo.FindPageAsOf(context.Background(), asOf, 2, 2)
+	suite.NoError(err)
+	suite.Len(second, 1)
+	suite.Equal("order-2", second[0].ID)
+
+	live, err := repo.FindPage(context.Background(), 2, 2)
+	suite.NoError(err)
+	suite.Len(live, 2)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrdersUpdatedOverTime_WhenFindUpdatedSince_ThenShouldReturnTheChangesInUpdateOrder() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	updates := map[string]time.Duration{
+		"unchanged": -time.Hour,
+		"late":      2 * time.Hour,
+		"early":     time.Hour,
+		"boundary":  0,
+	}
+	for id, offset := range updates {
+		order, err := entity.NewOrder(id, 10.0, 2.0)
+		suite.NoError(err)
+		order.CreatedAt = since.Add(-24 * time.Hour)
+		order.UpdatedAt = since.Add(offset)
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	orders, err := repo.FindUpdatedSince(context.Background(), entity.OrderChangeCursor{UpdatedAt: since}, 10)
+	suite.NoError(err)
+	suite.Len(orders, 2)
+	suite.Equal("early", orders[0].ID)
+	suite.Equal("late", orders[1].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrdersUpdatedInTheSameSecond_WhenFindUpdatedSinceACursor_ThenShouldResumeAfterIt() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, id := range []string{"c", "a", "b"} {
+		order, err := entity.NewOrder(id, 10.0, 2.0)
+		suite.NoError(err)
+		order.UpdatedAt = updatedAt
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	first, err := repo.FindUpdatedSince(context.Background(), entity.OrderChangeCursor{UpdatedAt: updatedAt.Add(-time.Second)}, 2)
+	suite.NoError(err)
+	suite.Len(first, 2)
+	next, err := repo.FindUpdatedSince(context.Background(), entity.NextOrderChangeCursor(first[1]), 2)
+	suite.NoError(err)
+	suite.Len(next, 1)
+	suite.Equal([]string{"a", "b", "c"}, []string{first[0].ID, first[1].ID, next[0].ID})
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenSeededOrders_WhenSearchByIDPrefix_ThenShouldMatchThePrefixIgnoringCase() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	for _, id := range []string{"ABC-1", "abc-2", "abd-3", "ab_c-4"} {
+		order, err := entity.NewOrder(id, 10.0, 2.0)
+		suite.NoError(err)
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	orders, err := repo.SearchByIDPrefix(context.Background(), "abc", 10)
+	suite.NoError(err)
+	suite.Len(orders, 2)
+	suite.Equal("ABC-1", orders[0].ID)
+	suite.Equal("abc-2", orders[1].ID)
+
+	orders, err = repo.SearchByIDPrefix(context.Background(), "ab_", 10)
+	suite.NoError(err)
+	suite.Len(orders, 1)
+	suite.Equal("ab_c-4", orders[0].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenCaseSensitiveSearch_WhenSearchByIDPrefix_ThenShouldMatchCaseExactly() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	repo.CaseSensitiveSearch = true
+	for _, id := range []string{"ABC-1", "abc-2"} {
+		order, err := entity.NewOrder(id, 10.0, 2.0)
+		suite.NoError(err)
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	orders, err := repo.SearchByIDPrefix(context.Background(), "abc", 10)
+
+	suite.NoError(err)
+	suite.Len(orders, 1)
+	suite.Equal("abc-2", orders[0].ID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAShortPrefix_WhenSearchByIDPrefix_ThenShouldRejectIt() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	repo.MinIDPrefixLength = 3
+
+	_, err := repo.SearchByIDPrefix(context.Background(), "ab", 10)
+
+	entitytest.AssertDomainError(suite.T(), err, entity.ErrIDPrefixTooShort)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAStatusFilter_WhenUpdateStatusWhere_ThenShouldOnlyUpdateMatchingOrders() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	cutoff := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i, seed := range []struct {
+		status    string
+		createdAt time.Time
+	}{
+		{entity.OrderStatusPending, cutoff.Add(-48 * time.Hour)},
+		{entity.OrderStatusPending, cutoff.Add(-time.Hour)},
+		{entity.OrderStatusPending, cutoff.Add(time.Hour)},
+		{entity.OrderStatusShipped, cutoff.Add(-48 * time.Hour)},
+	} {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.Status = seed.status
+		order.CreatedAt = seed.createdAt
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	filter := entity.OrderStatusFilter{Status: entity.OrderStatusPending, CreatedBefore: cutoff}
+	affected, err := repo.UpdateStatusWhere(context.Background(), filter, entity.OrderStatusCancelled, cutoff)
+	suite.NoError(err)
+	suite.Equal(2, affected)
+
+	counts, err := repo.CountByStatus(context.Background())
+	suite.NoError(err)
+	suite.Equal(map[string]int{entity.OrderStatusCancelled: 2, entity.OrderStatusPending: 1, entity.OrderStatusShipped: 1}, counts)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrders_WhenFindMostExpensive_ThenShouldReturnTheHighestFinalPriceOrNotFound() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	_, err := repo.FindMostExpensive(context.Background())
+	entitytest.AssertDomainError(suite.T(), err, entity.ErrOrderNotFound)
+
+	for _, seed := range []struct {
+		id         string
+		price, tax float64
+	}{{"cheap", 10, 1}, {"top", 90, 20}, {"taxed", 100, 5}} {
+		order, err := entity.NewOrder(seed.id, seed.price, seed.tax)
+		suite.NoError(err)
+		suite.NoError(order.CalculateFinalPrice())
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	order, err := repo.FindMostExpensive(context.Background())
+	suite.NoError(err)
+	suite.Equal("top", order.ID)
+	suite.Equal(110.0, order.FinalPrice)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOrdersInEveryStatus_WhenTotalRevenue_ThenShouldSkipTheCancelledOnes() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	total, err := repo.TotalRevenue(context.Background())
+	suite.NoError(err)
+	suite.Equal(0.0, total)
+
+	for i, status := range []string{entity.OrderStatusPending, entity.OrderStatusDelivered, entity.OrderStatusCancelled} {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.1, 0.2)
+		suite.NoError(err)
+		suite.NoError(order.CalculateFinalPrice())
+		order.Status = status
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+
+	total, err = repo.TotalRevenue(context.Background())
+	suite.NoError(err)
+	suite.Equal(20.6, total)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenOldOrders_WhenArchiveBefore_ThenShouldMoveThemInBatches() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	cutoff := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i, createdAt := range []time.Time{
+		cutoff.Add(-72 * time.Hour),
+		cutoff.Add(-48 * time.Hour),
+		cutoff.Add(-time.Hour),
+		cutoff.Add(time.Hour),
+	} {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		order.CreatedAt = createdAt
+		suite.NoError(repo.Save(context.Background(), order))
+	}
+	archivedAt := cutoff.Add(24 * time.Hour)
+
+	archived, err := repo.ArchiveBefore(context.Background(), cutoff, 2, archivedAt)
+	suite.NoError(err)
+	suite.Equal(2, archived)
+	archived, err = repo.ArchiveBefore(context.Background(), cutoff, 2, archivedAt)
+	suite.NoError(err)
+	suite.Equal(1, archived)
+
+	orders, err := repo.FindAll(context.Background())
+	suite.NoError(err)
+	suite.Len(orders, 1)
+	suite.Equal("order-3", orders[0].ID)
+
+	rows, err := suite.Db.Query("SELECT id, archived_at FROM orders_archive ORDER BY id")
+	suite.NoError(err)
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		var at time.Time
+		suite.NoError(rows.Scan(&id, &at))
+		suite.True(archivedAt.Equal(at))
+		ids = append(ids, id)
+	}
+	suite.Equal([]string{"order-0", "order-1", "order-2"}, ids)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenACreateAndAStatusChange_WhenFindHistory_ThenShouldListBothInOrder() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	order, err := entity.NewOrder("123", 10.0, 2.0)
+	suite.NoError(err)
+	suite.NoError(repo.Save(context.Background(), order))
+	other, err := entity.NewOrder("456", 10.0, 2.0)
+	suite.NoError(err)
+	suite.NoError(repo.Save(context.Background(), other))
+	processedAt := order.UpdatedAt.Add(time.Minute)
+	_, err = repo.UpdateStatusWhere(context.Background(), entity.OrderStatusFilter{Status: entity.OrderStatusPending}, entity.OrderStatusProcessing, processedAt)
+	suite.NoError(err)
+
+	history, err := repo.FindHistory(context.Background(), "123")
+	suite.NoError(err)
+	suite.Len(history, 2)
+	suite.Equal(entity.OrderChange{OrderID: "123", Action: entity.OrderChangeCreated, Status: entity.OrderStatusPending, ChangedAt: order.UpdatedAt}, history[0])
+	suite.Equal(entity.OrderChange{OrderID: "123", Action: entity.OrderChangeStatusChanged, Status: entity.OrderStatusProcessing, ChangedAt: processedAt}, history[1])
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAnOrder_WhenFindByID_ThenShouldReturnItOrNotFound() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	order, err := entity.NewOrder("123", 10.0, 2.0)
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	suite.NoError(repo.Save(context.Background(), order))
+
+	found, err := repo.FindByID(context.Background(), "123")
+	suite.NoError(err)
+	suite.Equal(order.ID, found.ID)
+	suite.Equal(order.FinalPrice, found.FinalPrice)
+	suite.Equal(order.Status, found.Status)
+
+	_, err = repo.FindByID(context.Background(), "missing")
+	entitytest.AssertDomainError(suite.T(), err, entity.ErrOrderNotFound)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenALegacyZeroPriceRow_WhenFindByID_ThenShouldReturnItWithoutValidating() {
+	_, err := suite.Db.Exec("INSERT INTO orders (id, price, tax, final_price) VALUES ('legacy', 0, 0, 0)")
+	suite.NoError(err)
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+
+	order, err := repo.FindByID(context.Background(), "legacy")
+	suite.NoError(err)
+	suite.Equal(0.0, order.Price)
+	suite.Equal(entity.OrderStatusPending, order.Status)
+
+	orders, err := repo.FindAll(context.Background())
+	suite.NoError(err)
+	suite.Len(orders, 1)
+	entitytest.AssertDomainError(suite.T(), order.IsValid(), entity.ErrInvalidPrice)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAColumnAddedByANewerMigration_WhenReadingAndWriting_ThenShouldIgnoreIt() {
+	_, err := suite.Db.Exec("ALTER TABLE orders ADD COLUMN customer_id varchar(255) NULL")
+	suite.NoError(err)
+	_, err = suite.Db.Exec("ALTER TABLE orders ADD COLUMN currency varchar(3) NOT NULL DEFAULT 'USD'")
+	suite.NoError(err)
+	_, err = suite.Db.Exec("INSERT INTO orders (id, price, tax, final_price, customer_id) VALUES ('new-pod', 10, 2, 12, 'customer-1')")
+	suite.NoError(err)
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	order, err := entity.NewOrder("old-pod", 20.0, 4.0)
+	suite.NoError(err)
+	suite.NoError(order.CalculateFinalPrice())
+	suite.NoError(repo.Save(context.Background(), order))
+
+	orders, err := repo.FindPage(context.Background(), 10, 0)
+	suite.NoError(err)
+	suite.Len(orders, 2)
+	found, err := repo.FindByID(context.Background(), "new-pod")
+	suite.NoError(err)
+	suite.Equal(12.0, found.FinalPrice)
+	suite.Equal(entity.OrderStatusPending, found.Status)
+	suite.Empty(found.TraceID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenABatchWithAFailingItem_WhenSaveBatch_ThenShouldRollBackAndReportTheIndex() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	var orders []*entity.Order
+	for _, id := range []string{"a", "b", "a"} {
+		order, err := entity.NewOrder(id, 10.0, 2.0)
+		suite.NoError(err)
+		orders = append(orders, order)
+	}
+
+	err := repo.SaveBatch(context.Background(), orders)
+
+	var itemErr *entity.BatchItemError
+	suite.True(errors.As(err, &itemErr))
+	suite.Equal(2, itemErr.Index)
+	total, err := repo.GetTotal(context.Background())
+	suite.NoError(err)
+	suite.Equal(0, total)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenEitherTotalStrategy_WhenFindPageWithTotal_ThenShouldCountEveryPage() {
+	for i := 0; i < 3; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("order-%d", i), 10.0, 2.0)
+		suite.NoError(err)
+		suite.NoError(order.CalculateFinalPrice())
+		suite.NoError(NewOrderRepository(suite.Db, DefaultRepositoryConfig()).Save(context.Background(), order))
+	}
+
+	for _, windowTotal := range []bool{false, true} {
+		repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+		repo.WindowTotal = windowTotal
+
+		orders, total, err := repo.FindPageWithTotal(context.Background(), 2, 1)
+		suite.NoError(err)
+		suite.Equal(3, total)
+		suite.Len(orders, 2)
+		suite.Equal("order-1", orders[0].ID)
+
+		orders, total, err = repo.FindPageWithTotal(context.Background(), 2, 10)
+		suite.NoError(err)
+		suite.Equal(3, total, "a page past the end still reports the total")
+		suite.Empty(orders)
+	}
+}
+
+func (suite *OrderRepositoryTestSuite) TestGivenAValidBatch_WhenSaveBatch_ThenShouldPersistEveryOrder() {
+	repo := NewOrderRepository(suite.Db, DefaultRepositoryConfig())
+	var orders []*entity.Order
+	for _, id := range []string{"a", "b", "c"} {
+		order, err := entity.NewOrder(id, 10.0, 2.0)
+		suite.NoError(err)
+		orders = append(orders, order)
+	}
+
+	suite.NoError(repo.SaveBatch(context.Background(), orders))
+
+	total, err := repo.GetTotal(context.Background())
+	suite.NoError(err)
+	suite.Equal(3, total)
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// orderColumns are the columns read for every entity.Order, in scanOrders
+// order.
+const orderColumns = "id, price, tax, final_price, status, created_at, updated_at, trace_id"
+
+// sortableColumns lists the columns orderQuery may sort by. Column names cannot
+// be bound as parameters, so anything else is rejected.
+var sortableColumns = map[string]bool{
+	"id":          true,
+	"price":       true,
+	"tax":         true,
+	"final_price": true,
+	"status":      true,
+	"created_at":  true,
+	"updated_at":  true,
+}
+
+// orderQuery assembles a parameterized query over the orders table. Conditions
+// are fixed SQL fragments with ? placeholders; values only ever travel as args.
+type orderQuery struct {
+	conditions []string
+	args       []interface{}
+	orderBy    []string
+	limit      int
+	offset     int
+	paged      bool
+	withTotal  bool
+	err        error
+}
+
+func newOrderQuery() *orderQuery {
+	return &orderQuery{}
+}
+
+// Where adds a condition, ANDed with the others.
+func (q *orderQuery) Where(condition string, args ...interface{}) *orderQuery {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+	return q
+}
+
+// OrderBy appends a sort column. Columns outside sortableColumns make Select
+// fail.
+func (q *orderQuery) OrderBy(column string, desc bool) *orderQuery {
+	if !sortableColumns[column] {
+		if q.err == nil {
+			q.err = fmt.Errorf("cannot sort orders by %q", column)
+		}
+		return q
+	}
+	if desc {
+		column += " DESC"
+	}
+	q.orderBy = append(q.orderBy, column)
+	return q
+}
+
+// Page limits the query to limit rows starting at offset. A zero offset is
+// left out of the SQL.
+func (q *orderQuery) Page(limit, offset int) *orderQuery {
+	q.limit, q.offset, q.paged = limit, offset, true
+	return q
+}
+
+// WithTotal adds a last column counting every row matching the conditions,
+// before the page is applied, so a page and its total take one query.
+func (q *orderQuery) WithTotal() *orderQuery {
+	q.withTotal = true
+	return q
+}
+
+// WhereClause returns the conditions as " WHERE ..." and their args, or an
+// empty clause when there are none.
+func (q *orderQuery) WhereClause() (string, []interface{}) {
+	if len(q.conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND "), q.args
+}
+
+// Select builds the SELECT of orderColumns with the conditions, sort and page.
+func (q *orderQuery) Select() (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	where, whereArgs := q.WhereClause()
+	var query strings.Builder
+	query.WriteString("SELECT " + orderColumns)
+	if q.withTotal {
+		query.WriteString(", COUNT(*) OVER () AS total")
+	}
+	query.WriteString(" FROM orders" + where)
+	args := append([]interface{}{}, whereArgs...)
+	if len(q.orderBy) > 0 {
+		query.WriteString(" ORDER BY " + strings.Join(q.orderBy, ", "))
+	}
+	if q.paged {
+		query.WriteString(" LIMIT ?")
+		args = append(args, q.limit)
+		if q.offset != 0 {
+			query.WriteString(" OFFSET ?")
+			args = append(args, q.offset)
+		}
+	}
+	return query.String(), args, nil
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenFilterCombinations_WhenSelect_ThenShouldBuildParameterizedSQL(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		query     *orderQuery
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			"no filters",
+			newOrderQuery(),
+			"SELECT " + orderColumns + " FROM orders",
+			[]interface{}{},
+		},
+		{
+			"sorted page",
+			newOrderQuery().OrderBy("created_at", false).OrderBy("id", false).Page(20, 40),
+			"SELECT " + orderColumns + " FROM orders ORDER BY created_at, id LIMIT ? OFFSET ?",
+			[]interface{}{20, 40},
+		},
+		{
+			"first page",
+			newOrderQuery().OrderBy("created_at", true).Page(10, 0),
+			"SELECT " + orderColumns + " FROM orders ORDER BY created_at DESC LIMIT ?",
+			[]interface{}{10},
+		},
+		{
+			"filters and page",
+			newOrderQuery().Where("status = ?", "pending").Where("updated_at > ?", since).OrderBy("updated_at", false).Page(5, 10),
+			"SELECT " + orderColumns + " FROM orders WHERE status = ? AND updated_at > ? ORDER BY updated_at LIMIT ? OFFSET ?",
+			[]interface{}{"pending", since, 5, 10},
+		},
+		{
+			"page with total",
+			newOrderQuery().Where("status = ?", "pending").OrderBy("id", false).Page(5, 10).WithTotal(),
+			"SELECT " + orderColumns + ", COUNT(*) OVER () AS total FROM orders WHERE status = ? ORDER BY id LIMIT ? OFFSET ?",
+			[]interface{}{"pending", 5, 10},
+		},
+		{
+			"injection attempt in a value",
+			newOrderQuery().Where("status = ?", "pending' OR '1'='1"),
+			"SELECT " + orderColumns + " FROM orders WHERE status = ?",
+			[]interface{}{"pending' OR '1'='1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.query.Select()
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestGivenAColumnOutsideTheWhitelist_WhenSelect_ThenShouldFail(t *testing.T) {
+	_, _, err := newOrderQuery().OrderBy("price; DROP TABLE orders", false).Select()
+
+	assert.EqualError(t, err, `cannot sort orders by "price; DROP TABLE orders"`)
+}
+
+func TestGivenConditions_WhenWhereClause_ThenShouldJoinThemWithAnd(t *testing.T) {
+	where, args := newOrderQuery().Where("status = ?", "pending").Where("created_at < ?", "2024-05-01").WhereClause()
+
+	assert.Equal(t, " WHERE status = ? AND created_at < ?", where)
+	assert.Equal(t, []interface{}{"pending", "2024-05-01"}, args)
+
+	where, args = newOrderQuery().WhereClause()
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CheckWindowFunctions fails when the server cannot run COUNT(*) OVER (),
+// which WindowTotal relies on. MySQL has window functions from 8.0 and
+// MariaDB, whose versions start at 10, from 10.2.
+func CheckWindowFunctions(ctx context.Context, db *sql.DB) error {
+	var version string
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return err
+	}
+	major, minor := parseServerVersion(version)
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		if major > 10 || major == 10 && minor >= 2 {
+			return nil
+		}
+	} else if major >= 8 {
+		return nil
+	}
+	return fmt.Errorf("window functions need MySQL 8.0 or MariaDB 10.2 or later, server is %s", version)
+}
+
+// parseServerVersion reads the major and minor numbers from a VERSION() such
+// as 5.7.44-log, leaving zero for what it cannot parse.
+func parseServerVersion(version string) (major, minor int) {
+	parts := strings.SplitN(version, ".", 3)
+	major, _ = strconv.Atoi(parts[0])
+	if len(parts) > 1 {
+		minor, _ = strconv.Atoi(parts[1])
+	}
+	return major, minor
+}
@@ -0,0 +1,27 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenServerVersions_WhenCheckWindowFunctions_ThenShouldOnlyAcceptThoseWithWindowFunctions(t *testing.T) {
+	for version, supported := range map[string]bool{
+		"5.7.44-log":      false,
+		"8.0.36":          true,
+		"10.1.48-MariaDB": false,
+		"10.6.12-MariaDB": true,
+	} {
+		db, mock, err := sqlmock.New()
+		assert.Nil(t, err)
+		mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(version))
+
+		err = CheckWindowFunctions(context.Background(), db)
+
+		assert.Equal(t, supported, err == nil, version)
+		db.Close()
+	}
+}
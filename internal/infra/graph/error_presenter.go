@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/pkg/pagination"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter gives every resolver error a "code" extension using the codes
+// of the REST error envelope. Domain and input errors keep their message;
+// database failures get a fixed one, and anything unexpected is masked as
+// "internal error" and logged with the "correlation_id" sent to the client,
+// so SQL errors and other internals never reach it. Errors raised by gqlgen
+// itself, such as validation errors, are left as they are. debug adds the
+// underlying message to masked errors, under the "debug" extension; it must
+// only be enabled in development.
+func ErrorPresenter(logger *slog.Logger, debug bool) graphql.ErrorPresenterFunc {
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		presented := graphql.DefaultErrorPresenter(ctx, err)
+		if presented.Err == nil {
+			return presented
+		}
+		cause := presented.Err
+
+		code, message := errorCode(cause)
+		if code == "INTERNAL_ERROR" {
+			correlationID := middleware.GetReqID(ctx)
+			if correlationID == "" {
+				correlationID = uuid.NewString()
+			}
+			logger.Error("graphql resolver failed", "correlation_id", correlationID, "path", presented.Path.String(), "error", cause)
+			presented.Extensions = map[string]interface{}{"correlation_id": correlationID}
+		}
+		if message != "" {
+			if debug {
+				presented.Extensions = withExtension(presented.Extensions, "debug", cause.Error())
+			}
+			presented.Message = message
+		}
+		presented.Extensions = withExtension(presented.Extensions, "code", code)
+		return presented
+	}
+}
+
+// errorCode returns the code of err and, when its own message must not be
+// sent, the message to send instead.
+func errorCode(err error) (code, message string) {
+	switch {
+	case errors.Is(err, entity.ErrOrderNotFound):
+		return "NOT_FOUND", ""
+	case errors.Is(err, entity.ErrInvalidID),
+		errors.Is(err, pagination.ErrNegativeLimit),
+		errors.Is(err, pagination.ErrNegativeOffset):
+		return "BAD_REQUEST", ""
+	case errors.Is(err, entity.ErrDomain):
+		return "UNPROCESSABLE_ENTITY", ""
+	case errors.Is(err, entity.ErrOrderAlreadyExists):
+		return "CONFLICT", "order already exists"
+	case errors.Is(err, entity.ErrStorageUnavailable):
+		return "SERVICE_UNAVAILABLE", "database unavailable"
+	}
+	return "INTERNAL_ERROR", "internal error"
+}
+
+func withExtension(extensions map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	if extensions == nil {
+		extensions = map[string]interface{}{}
+	}
+	extensions[key] = value
+	return extensions
+}
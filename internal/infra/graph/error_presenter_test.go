@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+// saveStub fails every Save with err.
+type saveStub struct {
+	entity.OrderRepositoryInterface
+	err error
+}
+
+func (s *saveStub) Save(ctx context.Context, order *entity.Order) error {
+	return s.err
+}
+
+type graphQLError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+func createOrder(t *testing.T, saveErr error, price float64, logs *bytes.Buffer, debug bool) graphQLError {
+	createOrderUseCase := usecase.NewCreateOrderUseCase(&saveStub{err: saveErr}, nil, events.NewNoopDispatcher())
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{CreateOrderUseCase: *createOrderUseCase}}))
+	srv.AddTransport(transport.POST{})
+	srv.SetErrorPresenter(ErrorPresenter(slog.New(slog.NewTextHandler(logs, nil)), debug))
+
+	query, _ := json.Marshal(map[string]interface{}{
+		"query":     `mutation($price: Float!) { createOrder(input: {id: "123", Price: $price, Tax: 2}) { id } }`,
+		"variables": map[string]interface{}{"price": price},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(query)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var body struct {
+		Errors []graphQLError `json:"errors"`
+	}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+	assert.Len(t, body.Errors, 1, rec.Body.String())
+	if len(body.Errors) == 0 {
+		return graphQLError{}
+	}
+	return body.Errors[0]
+}
+
+func TestGivenAnUnexpectedError_WhenCreateOrder_ThenShouldMaskItAndLogItsCorrelationID(t *testing.T) {
+	var logs bytes.Buffer
+
+	got := createOrder(t, errors.New("Error 1146: Table 'orders.orders' doesn't exist"), 10, &logs, false)
+
+	assert.Equal(t, "internal error", got.Message)
+	assert.Equal(t, "INTERNAL_ERROR", got.Extensions["code"])
+	assert.NotContains(t, got.Extensions, "debug")
+	correlationID, _ := got.Extensions["correlation_id"].(string)
+	assert.NotEmpty(t, correlationID)
+	assert.Contains(t, logs.String(), "correlation_id="+correlationID)
+	assert.Contains(t, logs.String(), "Table 'orders.orders' doesn't exist")
+}
+
+func TestGivenADomainError_WhenCreateOrder_ThenShouldKeepItsMessageAndCode(t *testing.T) {
+	var logs bytes.Buffer
+
+	got := createOrder(t, nil, -1, &logs, false)
+
+	assert.Equal(t, "invalid price", got.Message)
+	assert.Equal(t, "UNPROCESSABLE_ENTITY", got.Extensions["code"])
+	assert.NotContains(t, got.Extensions, "correlation_id")
+	assert.Empty(t, logs.String())
+}
+
+func TestGivenDebugErrors_WhenAnUnexpectedErrorIsMasked_ThenShouldAddTheUnderlyingMessage(t *testing.T) {
+	var logs bytes.Buffer
+
+	got := createOrder(t, errors.New("Error 1146: Table 'orders.orders' doesn't exist"), 10, &logs, true)
+
+	assert.Equal(t, "internal error", got.Message)
+	assert.Equal(t, "Error 1146: Table 'orders.orders' doesn't exist", got.Extensions["debug"])
+}
+
+func TestGivenAStorageError_WhenCreateOrder_ThenShouldReplaceItsMessageAndSetItsCode(t *testing.T) {
+	for sentinel, code := range map[error]string{
+		entity.ErrOrderAlreadyExists: "CONFLICT",
+		entity.ErrStorageUnavailable: "SERVICE_UNAVAILABLE",
+	} {
+		var logs bytes.Buffer
+
+		presented := createOrder(t, fmt.Errorf("%w: %w", sentinel, errors.New("Error 1062: Duplicate entry")), 10, &logs, false)
+
+		assert.Equal(t, code, presented.Extensions["code"], sentinel.Error())
+		assert.NotContains(t, presented.Message, "Error 1062")
+	}
+}
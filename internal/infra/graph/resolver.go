@@ -1,6 +1,9 @@
 package graph
 
-import "github.com/mvr-garcia/go-clean-arch/internal/usecase"
+import (
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/mvr-garcia/go-clean-arch/pkg/pagination"
+)
 
 // This file will not be regenerated automatically.
 //
@@ -9,4 +12,9 @@ import "github.com/mvr-garcia/go-clean-arch/internal/usecase"
 type Resolver struct {
 	CreateOrderUseCase usecase.CreateOrderUseCase
 	ListOrdersUseCase  usecase.ListOrdersUseCase
+	// Pagination bounds the listOrders page size.
+	Pagination pagination.Limits
+	// MaxOrderIDLength bounds the IDs createOrder accepts. Zero means
+	// entity.MaxOrderIDLength.
+	MaxOrderIDLength int
 }
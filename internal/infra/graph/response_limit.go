@@ -0,0 +1,20 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// ResponseSizeLimit replaces any response whose data is larger than maxBytes
+// with an error, so a query selecting too many orders fails instead of
+// streaming a huge payload. A maxBytes of zero or less disables the limit.
+func ResponseSizeLimit(maxBytes int) graphql.ResponseMiddleware {
+	return func(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+		resp := next(ctx)
+		if maxBytes <= 0 || resp == nil || len(resp.Data) <= maxBytes {
+			return resp
+		}
+		return graphql.ErrorResponse(ctx, "response exceeds the limit of %d bytes; request fewer orders or fields", maxBytes)
+	}
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+// pageStub serves FindPage from a fixed set of orders.
+type pageStub struct {
+	entity.OrderRepositoryInterface
+	orders []entity.Order
+}
+
+func (p *pageStub) FindPage(ctx context.Context, limit, offset int) ([]entity.Order, error) {
+	return p.orders, nil
+}
+
+func newLimitedServer(orders int, maxBytes int) *handler.Server {
+	stub := &pageStub{}
+	for i := 0; i < orders; i++ {
+		stub.orders = append(stub.orders, entity.Order{ID: fmt.Sprintf("order-%d", i), Price: 10, Tax: 2, FinalPrice: 12})
+	}
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: &Resolver{
+		ListOrdersUseCase: usecase.ListOrdersUseCase{OrderRepository: stub},
+	}}))
+	srv.AddTransport(transport.POST{})
+	srv.AroundResponses(ResponseSizeLimit(maxBytes))
+	return srv
+}
+
+func listOrders(srv http.Handler) string {
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"{listOrders{id Price Tax FinalPrice}}"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestGivenAResponseSizeLimit_WhenTheResultIsLarger_ThenShouldRejectIt(t *testing.T) {
+	body := listOrders(newLimitedServer(50, 1024))
+
+	assert.Contains(t, body, "response exceeds the limit of 1024 bytes")
+	assert.NotContains(t, body, "order-0")
+}
+
+func TestGivenAResponseSizeLimit_WhenTheResultFits_ThenShouldReturnIt(t *testing.T) {
+	body := listOrders(newLimitedServer(2, 1024))
+
+	assert.JSONEq(t, `{"data":{"listOrders":[
+		{"id":"order-0","Price":10,"Tax":2,"FinalPrice":12},
+		{"id":"order-1","Price":10,"Tax":2,"FinalPrice":12}
+	]}}`, body)
+}
@@ -8,18 +8,23 @@ package graph
 import (
 	"context"
 
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
 	"github.com/mvr-garcia/go-clean-arch/internal/infra/graph/model"
 	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
 )
 
 // CreateOrder is the resolver for the createOrder field.
 func (r *mutationResolver) CreateOrder(ctx context.Context, input *model.OrderInput) (*model.Order, error) {
+	id, err := entity.NormalizeOrderID(input.ID, r.MaxOrderIDLength)
+	if err != nil {
+		return nil, err
+	}
 	dto := usecase.OrderInputDTO{
-		ID:    input.ID,
+		ID:    id,
 		Price: float64(input.Price),
 		Tax:   float64(input.Tax),
 	}
-	output, err := r.CreateOrderUseCase.Execute(dto)
+	output, err := r.CreateOrderUseCase.Execute(ctx, dto)
 	if err != nil {
 		return nil, err
 	}
@@ -32,13 +37,25 @@ func (r *mutationResolver) CreateOrder(ctx context.Context, input *model.OrderIn
 }
 
 // ListOrders is the resolver for the listOrders field.
-func (r *queryResolver) ListOrders(ctx context.Context) ([]*model.Order, error) {
-	output, err := r.ListOrdersUseCase.Execute()
+func (r *queryResolver) ListOrders(ctx context.Context, limit *int, offset *int) ([]*model.Order, error) {
+	var pageLimit, pageOffset int
+	if limit != nil {
+		pageLimit = *limit
+	}
+	if offset != nil {
+		pageOffset = *offset
+	}
+	page, err := r.Pagination.Parse(pageLimit, pageOffset)
 	if err != nil {
 		return nil, err
 	}
 
-	var orders []*model.Order
+	output, err := r.ListOrdersUseCase.Execute(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*model.Order, 0, len(output.Orders))
 	for _, order := range output.Orders {
 		orders = append(orders, &model.Order{
 			ID:         order.ID,
@@ -59,15 +76,3 @@ func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
-
-// !!! WARNING !!!
-// The code below was going to be deleted when updating resolvers. It has been copied here so you have
-// one last chance to move it out of harms way if you want. There are two reasons this happens:
-//  - When renaming or deleting a resolver the old code will be put in here. You can safely delete
-//    it when you're done.
-//  - You have helper methods in this file. Move them out to keep these resolver files clean.
-/*
-	func (r *mutationResolver) ListOrders(ctx context.Context) ([]*model.Order, error) {
-	panic(fmt.Errorf("not implemented: ListOrders - listOrders"))
-}
-*/
@@ -0,0 +1,23 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnEmptyOrWhitespaceID_WhenCreateOrder_ThenShouldRejectItBeforeTheUseCase(t *testing.T) {
+	// The zero CreateOrderUseCase has no repository, so reaching it panics.
+	resolver := &mutationResolver{&Resolver{}}
+
+	for _, id := range []string{"", " \t "} {
+		order, err := resolver.CreateOrder(context.Background(), &model.OrderInput{ID: id, Price: 10, Tax: 2})
+
+		assert.Nil(t, order)
+		entitytest.AssertDomainError(t, err, entity.ErrInvalidID, "id %q", id)
+	}
+}
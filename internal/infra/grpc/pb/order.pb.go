@@ -9,7 +9,6 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -150,6 +149,58 @@ func (x *CreateOrderResponse) GetFinalPrice() float32 {
 	return 0
 }
 
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_internal_infra_grpc_protofiles_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_infra_grpc_protofiles_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_internal_infra_grpc_protofiles_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListOrdersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
 type ListOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Orders        []*CreateOrderResponse `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
@@ -159,7 +210,7 @@ type ListOrdersResponse struct {
 
 func (x *ListOrdersResponse) Reset() {
 	*x = ListOrdersResponse{}
-	mi := &file_internal_infra_grpc_protofiles_order_proto_msgTypes[2]
+	mi := &file_internal_infra_grpc_protofiles_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -171,7 +222,7 @@ func (x *ListOrdersResponse) String() string {
 func (*ListOrdersResponse) ProtoMessage() {}
 
 func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_infra_grpc_protofiles_order_proto_msgTypes[2]
+	mi := &file_internal_infra_grpc_protofiles_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -184,7 +235,7 @@ func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
 func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_internal_infra_grpc_protofiles_order_proto_rawDescGZIP(), []int{2}
+	return file_internal_infra_grpc_protofiles_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ListOrdersResponse) GetOrders() []*CreateOrderResponse {
@@ -198,7 +249,7 @@ var File_internal_infra_grpc_protofiles_order_proto protoreflect.FileDescriptor
 
 const file_internal_infra_grpc_protofiles_order_proto_rawDesc = "" +
 	"\n" +
-	"*internal/infra/grpc/protofiles/order.proto\x12\x02pb\x1a\x1bgoogle/protobuf/empty.proto\"L\n" +
+	"*internal/infra/grpc/protofiles/order.proto\x12\x02pb\"L\n" +
 	"\x12CreateOrderRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x02R\x05price\x12\x10\n" +
@@ -208,13 +259,16 @@ const file_internal_infra_grpc_protofiles_order_proto_rawDesc = "" +
 	"\x05price\x18\x02 \x01(\x02R\x05price\x12\x10\n" +
 	"\x03tax\x18\x03 \x01(\x02R\x03tax\x12\x1f\n" +
 	"\vfinal_price\x18\x04 \x01(\x02R\n" +
-	"finalPrice\"E\n" +
+	"finalPrice\"A\n" +
+	"\x11ListOrdersRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"E\n" +
 	"\x12ListOrdersResponse\x12/\n" +
-	"\x06orders\x18\x01 \x03(\v2\x17.pb.CreateOrderResponseR\x06orders2\x8c\x01\n" +
+	"\x06orders\x18\x01 \x03(\v2\x17.pb.CreateOrderResponseR\x06orders2\x8b\x01\n" +
 	"\fOrderService\x12>\n" +
-	"\vCreateOrder\x12\x16.pb.CreateOrderRequest\x1a\x17.pb.CreateOrderResponse\x12<\n" +
+	"\vCreateOrder\x12\x16.pb.CreateOrderRequest\x1a\x17.pb.CreateOrderResponse\x12;\n" +
 	"\n" +
-	"ListOrders\x12\x16.google.protobuf.Empty\x1a\x16.pb.ListOrdersResponseB\x18Z\x16internal/infra/grpc/pbb\x06proto3"
+	"ListOrders\x12\x15.pb.ListOrdersRequest\x1a\x16.pb.ListOrdersResponseB\x18Z\x16internal/infra/grpc/pbb\x06proto3"
 
 var (
 	file_internal_infra_grpc_protofiles_order_proto_rawDescOnce sync.Once
@@ -228,19 +282,19 @@ func file_internal_infra_grpc_protofiles_order_proto_rawDescGZIP() []byte {
 	return file_internal_infra_grpc_protofiles_order_proto_rawDescData
 }
 
-var file_internal_infra_grpc_protofiles_order_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_internal_infra_grpc_protofiles_order_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_internal_infra_grpc_protofiles_order_proto_goTypes = []any{
 	(*CreateOrderRequest)(nil),  // 0: pb.CreateOrderRequest
 	(*CreateOrderResponse)(nil), // 1: pb.CreateOrderResponse
-	(*ListOrdersResponse)(nil),  // 2: pb.ListOrdersResponse
-	(*emptypb.Empty)(nil),       // 3: google.protobuf.Empty
+	(*ListOrdersRequest)(nil),   // 2: pb.ListOrdersRequest
+	(*ListOrdersResponse)(nil),  // 3: pb.ListOrdersResponse
 }
 var file_internal_infra_grpc_protofiles_order_proto_depIdxs = []int32{
 	1, // 0: pb.ListOrdersResponse.orders:type_name -> pb.CreateOrderResponse
 	0, // 1: pb.OrderService.CreateOrder:input_type -> pb.CreateOrderRequest
-	3, // 2: pb.OrderService.ListOrders:input_type -> google.protobuf.Empty
+	2, // 2: pb.OrderService.ListOrders:input_type -> pb.ListOrdersRequest
 	1, // 3: pb.OrderService.CreateOrder:output_type -> pb.CreateOrderResponse
-	2, // 4: pb.OrderService.ListOrders:output_type -> pb.ListOrdersResponse
+	3, // 4: pb.OrderService.ListOrders:output_type -> pb.ListOrdersResponse
 	3, // [3:5] is the sub-list for method output_type
 	1, // [1:3] is the sub-list for method input_type
 	1, // [1:1] is the sub-list for extension type_name
@@ -259,7 +313,7 @@ func file_internal_infra_grpc_protofiles_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_infra_grpc_protofiles_order_proto_rawDesc), len(file_internal_infra_grpc_protofiles_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2,16 +2,29 @@ package service
 
 import (
 	"context"
+	"errors"
 
+	"github.com/google/uuid"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
 	"github.com/mvr-garcia/go-clean-arch/internal/infra/grpc/pb"
 	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
-	"google.golang.org/protobuf/types/known/emptypb"
+	"github.com/mvr-garcia/go-clean-arch/pkg/pagination"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type OrderService struct {
 	pb.UnimplementedOrderServiceServer
 	CreateOrderUseCase usecase.CreateOrderUseCase
 	ListOrdersUseCase  usecase.ListOrdersUseCase
+	// GenerateMissingIDs assigns a UUID to orders created without an ID
+	// instead of rejecting them. The response carries the assigned ID.
+	GenerateMissingIDs bool
+	// Pagination bounds the ListOrders page size.
+	Pagination pagination.Limits
+	// MaxOrderIDLength bounds the IDs CreateOrder accepts. Zero means
+	// entity.MaxOrderIDLength.
+	MaxOrderIDLength int
 }
 
 func NewOrderService(
@@ -25,13 +38,30 @@ func NewOrderService(
 }
 
 func (s *OrderService) CreateOrder(ctx context.Context, in *pb.CreateOrderRequest) (*pb.CreateOrderResponse, error) {
+	id := in.Id
+	if id == "" && s.GenerateMissingIDs {
+		id = uuid.NewString()
+	}
+	id, err := entity.NormalizeOrderID(id, s.MaxOrderIDLength)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	dto := usecase.OrderInputDTO{
-		ID:    in.Id,
+		ID:    id,
 		Price: float64(in.Price),
 		Tax:   float64(in.Tax),
 	}
-	output, err := s.CreateOrderUseCase.Execute(dto)
+	output, err := s.CreateOrderUseCase.Execute(ctx, dto)
 	if err != nil {
+		if errors.Is(err, entity.ErrDomain) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		switch {
+		case errors.Is(err, entity.ErrOrderAlreadyExists):
+			return nil, status.Error(codes.AlreadyExists, "order already exists")
+		case errors.Is(err, entity.ErrStorageUnavailable):
+			return nil, status.Error(codes.Unavailable, "database unavailable")
+		}
 		return nil, err
 	}
 	return &pb.CreateOrderResponse{
@@ -42,13 +72,18 @@ func (s *OrderService) CreateOrder(ctx context.Context, in *pb.CreateOrderReques
 	}, nil
 }
 
-func (s *OrderService) ListOrders(context.Context, *emptypb.Empty) (*pb.ListOrdersResponse, error) {
-	output, err := s.ListOrdersUseCase.Execute()
+func (s *OrderService) ListOrders(ctx context.Context, in *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	page, err := s.Pagination.Parse(int(in.Limit), int(in.Offset))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	output, err := s.ListOrdersUseCase.Execute(ctx, page)
 	if err != nil {
 		return nil, err
 	}
 
-	var orders []*pb.CreateOrderResponse
+	orders := make([]*pb.CreateOrderResponse, 0, len(output.Orders))
 	for _, order := range output.Orders {
 		orders = append(orders, &pb.CreateOrderResponse{
 			Id:         order.ID,
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/grpc/pb"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/mvr-garcia/go-clean-arch/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// pageRecorder records the page requested from the repository. Any other
+// repository method panics, as the list path must not touch them.
+type pageRecorder struct {
+	entity.OrderRepositoryInterface
+	limit, offset int
+}
+
+func (p *pageRecorder) FindPage(ctx context.Context, limit, offset int) ([]entity.Order, error) {
+	p.limit, p.offset = limit, offset
+	return []entity.Order{}, nil
+}
+
+// saveRecorder accepts every order saved to it, unless err is set.
+type saveRecorder struct {
+	entity.OrderRepositoryInterface
+	saved []entity.Order
+	err   error
+}
+
+func (s *saveRecorder) Save(ctx context.Context, order *entity.Order) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.saved = append(s.saved, *order)
+	return nil
+}
+
+func newBufconnClient(t *testing.T, svc *OrderService) pb.OrderServiceClient {
+	server := grpc.NewServer()
+	pb.RegisterOrderServiceServer(server, svc)
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewOrderServiceClient(conn)
+}
+
+func TestGivenGenerateMissingIDs_WhenCreateOrderWithoutAnID_ThenShouldReturnTheGeneratedID(t *testing.T) {
+	repository := &saveRecorder{}
+	svc := NewOrderService(*usecase.NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil), usecase.ListOrdersUseCase{})
+	svc.GenerateMissingIDs = true
+	client := newBufconnClient(t, svc)
+
+	output, err := client.CreateOrder(context.Background(), &pb.CreateOrderRequest{Price: 10, Tax: 2})
+
+	assert.Nil(t, err)
+	assert.Nil(t, uuid.Validate(output.Id))
+	assert.Len(t, repository.saved, 1)
+	assert.Equal(t, repository.saved[0].ID, output.Id)
+}
+
+func TestGivenAMalformedID_WhenCreateOrder_ThenShouldReturnInvalidArgument(t *testing.T) {
+	repository := &saveRecorder{}
+	svc := NewOrderService(*usecase.NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil), usecase.ListOrdersUseCase{})
+	client := newBufconnClient(t, svc)
+
+	for _, id := range []string{"", "   ", "order 1"} {
+		_, err := client.CreateOrder(context.Background(), &pb.CreateOrderRequest{Id: id, Price: 10, Tax: 2})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "id %q", id)
+		assert.Contains(t, status.Convert(err).Message(), "invalid id", "id %q", id)
+	}
+	assert.Empty(t, repository.saved)
+}
+
+func TestGivenAStorageError_WhenCreateOrder_ThenShouldMapItToItsCode(t *testing.T) {
+	for sentinel, code := range map[error]codes.Code{
+		entity.ErrOrderAlreadyExists: codes.AlreadyExists,
+		entity.ErrStorageUnavailable: codes.Unavailable,
+	} {
+		repository := &saveRecorder{err: fmt.Errorf("%w: %w", sentinel, errors.New("driver error"))}
+		svc := NewOrderService(*usecase.NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil), usecase.ListOrdersUseCase{})
+
+		_, err := newBufconnClient(t, svc).CreateOrder(context.Background(), &pb.CreateOrderRequest{Id: "123", Price: 10, Tax: 2})
+
+		assert.Equal(t, code, status.Code(err), sentinel.Error())
+		assert.NotContains(t, status.Convert(err).Message(), "driver error")
+	}
+}
+
+func TestGivenTheSameParams_WhenListingOverGRPCAndREST_ThenShouldRequestTheSamePage(t *testing.T) {
+	grpcRepository := &pageRecorder{}
+	svc := NewOrderService(usecase.CreateOrderUseCase{}, *usecase.NewListOrdersUseCase(grpcRepository))
+	_, err := svc.ListOrders(context.Background(), &pb.ListOrdersRequest{Limit: 500, Offset: 10})
+	assert.Nil(t, err)
+
+	restRepository := &pageRecorder{}
+	handler := web.NewWebOrderHandler(events.NewNoopDispatcher(), restRepository, nil)
+	rec := httptest.NewRecorder()
+	handler.List(rec, httptest.NewRequest(http.MethodGet, "/order?limit=500&offset=10", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, pagination.MaxLimit, grpcRepository.limit)
+	assert.Equal(t, *grpcRepository, *restRepository)
+}
+
+func TestGivenANegativeLimit_WhenListOrders_ThenShouldReturnInvalidArgument(t *testing.T) {
+	svc := NewOrderService(usecase.CreateOrderUseCase{}, *usecase.NewListOrdersUseCase(&pageRecorder{}))
+
+	_, err := svc.ListOrders(context.Background(), &pb.ListOrdersRequest{Limit: -1})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGivenNoOrders_WhenListOrdersOverGRPC_ThenShouldReturnAnEmptyList(t *testing.T) {
+	client := newBufconnClient(t, NewOrderService(usecase.CreateOrderUseCase{}, *usecase.NewListOrdersUseCase(&pageRecorder{})))
+
+	output, err := client.ListOrders(context.Background(), &pb.ListOrdersRequest{})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, output)
+	assert.Empty(t, output.Orders)
+}
+
+func TestGivenNoOrders_WhenListOrders_ThenShouldReturnANonNilEmptyList(t *testing.T) {
+	svc := NewOrderService(usecase.CreateOrderUseCase{}, *usecase.NewListOrdersUseCase(&pageRecorder{}))
+
+	output, err := svc.ListOrders(context.Background(), &pb.ListOrdersRequest{})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, output.Orders)
+	assert.Len(t, output.Orders, 0)
+}
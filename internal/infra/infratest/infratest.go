@@ -0,0 +1,105 @@
+//go:build integration
+
+// Package infratest starts the services integration tests run against, MySQL
+// and RabbitMQ, in Docker containers with testcontainers-go. It and every test
+// using it are behind the integration build tag, so the default test run
+// needs neither Docker nor the network:
+//
+//	go test -tags integration ./...
+package infratest
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/database"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/rabbitmq"
+	"github.com/streadway/amqp"
+	"github.com/testcontainers/testcontainers-go"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+	tcrabbitmq "github.com/testcontainers/testcontainers-go/modules/rabbitmq"
+
+	// mysql
+	_ "github.com/go-sql-driver/mysql"
+
+	// migrate mysql driver
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+)
+
+// Images used for the containers, the ones docker-compose.yaml runs.
+var (
+	MySQLImage    = "mysql:5.7"
+	RabbitMQImage = "rabbitmq:3-management"
+)
+
+// startTimeout bounds how long a container may take to start and accept
+// connections, image pull included.
+const startTimeout = 3 * time.Minute
+
+// MySQL starts a MySQL container, applies every migration to it and returns a
+// connection opened like the server opens its own. The container is removed
+// when the test ends; tests sharing it should clean up the rows they create.
+func MySQL(t testing.TB) *sql.DB {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	container, err := tcmysql.Run(ctx, MySQLImage,
+		tcmysql.WithDatabase("orders"),
+		tcmysql.WithUsername("orders"),
+		tcmysql.WithPassword("orders"),
+	)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start MySQL: %v", err)
+	}
+	dsn, err := container.ConnectionString(ctx, "parseTime=true")
+	if err != nil {
+		t.Fatalf("MySQL connection string: %v", err)
+	}
+
+	migrator, err := database.NewMigrator("mysql://" + dsn + "&multiStatements=true")
+	if err != nil {
+		t.Fatalf("open migrator: %v", err)
+	}
+	defer migrator.Close()
+	if err := database.RunMigrations(ctx, migrator, 0, slog.Default()); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatalf("open MySQL: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// RabbitMQ starts a RabbitMQ container and returns a connection to it made
+// through rabbitmq.Connector, as the server connects. The connection is closed
+// and the container removed when the test ends.
+func RabbitMQ(t testing.TB) *amqp.Connection {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	container, err := tcrabbitmq.Run(ctx, RabbitMQImage)
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("start RabbitMQ: %v", err)
+	}
+	url, err := container.AmqpURL(ctx)
+	if err != nil {
+		t.Fatalf("RabbitMQ URL: %v", err)
+	}
+
+	conn, err := rabbitmq.NewConnector(url).Connect(ctx)
+	if err != nil {
+		t.Fatalf("connect to RabbitMQ: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
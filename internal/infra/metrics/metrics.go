@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Outcomes recorded by EventsDispatched.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// EventsDispatched counts event deliveries by event name and outcome.
+var EventsDispatched = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "events_dispatched_total",
+		Help: "Events handed to the broker, by event name and outcome.",
+	},
+	[]string{"event", "outcome"},
+)
+
+// HTTPInFlightRequests is the number of REST requests being served.
+var HTTPInFlightRequests = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "http_in_flight_requests",
+		Help: "REST requests currently being served.",
+	},
+)
+
+// DefaultHTTPDurationBuckets suit the REST API, whose responses mostly take a
+// few milliseconds: prometheus.DefBuckets start at 5ms and would put nearly
+// every request in the first bucket. They are replaced at startup through
+// HTTP_DURATION_BUCKETS.
+var DefaultHTTPDurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// RegisterHTTPRequestDuration registers the REST request duration histogram,
+// by method, route pattern and status, with buckets as its upper bounds in
+// seconds. It is registered at startup rather than in init so the buckets can
+// come from configuration.
+func RegisterHTTPRequestDuration(registerer prometheus.Registerer, buckets []float64) (*prometheus.HistogramVec, error) {
+	histogram := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to serve REST requests, by method, route and status.",
+			Buckets: buckets,
+		},
+		[]string{"method", "route", "status"},
+	)
+	if err := registerer.Register(histogram); err != nil {
+		return nil, err
+	}
+	return histogram, nil
+}
+
+// ParseBuckets parses a comma separated list of histogram upper bounds, such
+// as "0.005,0.01,0.05". The bounds must be strictly increasing.
+func ParseBuckets(value string) ([]float64, error) {
+	var buckets []float64
+	for _, entry := range strings.Split(value, ",") {
+		bound, err := strconv.ParseFloat(strings.TrimSpace(entry), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid histogram bucket %q", entry)
+		}
+		if len(buckets) > 0 && bound <= buckets[len(buckets)-1] {
+			return nil, fmt.Errorf("histogram buckets must be strictly increasing, got %v after %v", bound, buckets[len(buckets)-1])
+		}
+		buckets = append(buckets, bound)
+	}
+	return buckets, nil
+}
+
+func init() {
+	prometheus.MustRegister(EventsDispatched, HTTPInFlightRequests)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenCustomBuckets_WhenRegisterHTTPRequestDuration_ThenTheRegisteredHistogramShouldUseThem(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	histogram, err := RegisterHTTPRequestDuration(registry, []float64{0.002, 0.004, 0.008})
+	assert.Nil(t, err)
+	histogram.WithLabelValues("GET", "/order", "200").Observe(0.003)
+
+	families, err := registry.Gather()
+	assert.Nil(t, err)
+	assert.Len(t, families, 1)
+	assert.Equal(t, "http_request_duration_seconds", families[0].GetName())
+	var bounds []float64
+	var counts []uint64
+	for _, bucket := range families[0].GetMetric()[0].GetHistogram().GetBucket() {
+		bounds = append(bounds, bucket.GetUpperBound())
+		counts = append(counts, bucket.GetCumulativeCount())
+	}
+	assert.Equal(t, []float64{0.002, 0.004, 0.008}, bounds)
+	assert.Equal(t, []uint64{0, 1, 1}, counts)
+}
+
+func TestGivenAnAlreadyRegisteredHistogram_WhenRegisterHTTPRequestDuration_ThenShouldFail(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := RegisterHTTPRequestDuration(registry, DefaultHTTPDurationBuckets)
+	assert.Nil(t, err)
+
+	_, err = RegisterHTTPRequestDuration(registry, DefaultHTTPDurationBuckets)
+
+	assert.NotNil(t, err)
+}
+
+func TestGivenABucketList_WhenParseBuckets_ThenShouldParseItOrRejectUnorderedBounds(t *testing.T) {
+	buckets, err := ParseBuckets("0.001, 0.005,0.01")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.001, 0.005, 0.01}, buckets)
+
+	_, err = ParseBuckets("0.01,0.005")
+	assert.EqualError(t, err, "histogram buckets must be strictly increasing, got 0.005 after 0.01")
+
+	_, err = ParseBuckets("0.01,fast")
+	assert.EqualError(t, err, `invalid histogram bucket "fast"`)
+
+	_, err = ParseBuckets("")
+	assert.EqualError(t, err, `invalid histogram bucket ""`)
+}
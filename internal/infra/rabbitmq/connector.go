@@ -0,0 +1,68 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+type Dialer func(url string) (*amqp.Connection, error)
+
+// Connector dials RabbitMQ, retrying with exponential backoff until a broker
+// is reachable or the context passed to Connect is done. URLs are tried in
+// order: when one is unreachable the next is dialed right away, and the
+// backoff only applies once every URL has failed.
+type Connector struct {
+	URLs           []string
+	Dial           Dialer
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Logger         *slog.Logger
+}
+
+func NewConnector(urls ...string) *Connector {
+	return &Connector{
+		URLs:           urls,
+		Dial:           amqp.Dial,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Logger:         slog.Default(),
+	}
+}
+
+func (c *Connector) Connect(ctx context.Context) (*amqp.Connection, error) {
+	if len(c.URLs) == 0 {
+		return nil, errors.New("no rabbitmq url configured")
+	}
+
+	backoff := c.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		var err error
+		for i, url := range c.URLs {
+			var conn *amqp.Connection
+			conn, err = c.Dial(url)
+			if err == nil {
+				return conn, nil
+			}
+			if i < len(c.URLs)-1 {
+				c.Logger.Warn("rabbitmq broker is not reachable, failing over", "broker", i, "error", err)
+			}
+		}
+		c.Logger.Warn("rabbitmq is not reachable, retrying", "attempt", attempt, "retry_in", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for rabbitmq: %w (last error: %v)", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > c.MaxBackoff {
+			backoff = c.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/streadway/amqp"
+)
+
+// ErrNotConnected is returned by Publish while the Publisher is reconnecting.
+var ErrNotConnected = errors.New("rabbitmq is not connected")
+
+// session is the connection and channel a Publisher publishes on.
+type session interface {
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	// NotifyClose returns a channel that receives once the connection or the
+	// channel closes.
+	NotifyClose() <-chan *amqp.Error
+	Close() error
+}
+
+// Publisher publishes on a channel of a connection established by Connector.
+// When the connection or the channel is lost, it reconnects through the URL
+// list in the background, so a broker going away fails over to the next one.
+// Messages published while it reconnects are not buffered: Publish returns
+// ErrNotConnected.
+type Publisher struct {
+	connector *Connector
+	open      func(ctx context.Context) (session, error)
+
+	mu      sync.RWMutex
+	current session
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+func NewPublisher(connector *Connector) *Publisher {
+	p := &Publisher{connector: connector}
+	p.open = p.openSession
+	return p
+}
+
+// Start connects, giving up when ctx is done, then keeps the connection up
+// until Close.
+func (p *Publisher) Start(ctx context.Context) error {
+	s, err := p.open(ctx)
+	if err != nil {
+		return err
+	}
+	watchCtx, stop := context.WithCancel(context.Background())
+	p.mu.Lock()
+	p.current, p.stop, p.done = s, stop, make(chan struct{})
+	p.mu.Unlock()
+	go p.watch(watchCtx, s)
+	return nil
+}
+
+func (p *Publisher) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.current == nil {
+		return ErrNotConnected
+	}
+	return p.current.Publish(exchange, key, mandatory, immediate, msg)
+}
+
+// Close stops reconnecting and closes the current connection.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.mu.Unlock()
+	if stop == nil {
+		return nil
+	}
+	stop()
+	<-done
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil {
+		return nil
+	}
+	err := p.current.Close()
+	p.current = nil
+	return err
+}
+
+// watch replaces s with a new session every time it closes, until ctx is done.
+func (p *Publisher) watch(ctx context.Context, s session) {
+	defer close(p.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case reason := <-s.NotifyClose():
+			p.connector.Logger.Warn("rabbitmq connection lost, reconnecting", "error", reason)
+		}
+		p.mu.Lock()
+		p.current = nil
+		p.mu.Unlock()
+		s.Close()
+
+		next, err := p.open(ctx)
+		if err != nil {
+			// Only a done ctx stops Connect from retrying.
+			return
+		}
+		p.mu.Lock()
+		p.current = next
+		p.mu.Unlock()
+		p.connector.Logger.Info("rabbitmq reconnected")
+		s = next
+	}
+}
+
+func (p *Publisher) openSession(ctx context.Context) (session, error) {
+	conn, err := p.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return newAMQPSession(conn, ch), nil
+}
+
+type amqpSession struct {
+	*amqp.Channel
+	conn   *amqp.Connection
+	closed chan *amqp.Error
+}
+
+func newAMQPSession(conn *amqp.Connection, ch *amqp.Channel) *amqpSession {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+	closed := make(chan *amqp.Error, 1)
+	go func() {
+		// amqp closes both channels on shutdown, so this always returns.
+		select {
+		case reason := <-connClosed:
+			closed <- reason
+		case reason := <-chClosed:
+			closed <- reason
+		}
+	}()
+	return &amqpSession{Channel: ch, conn: conn, closed: closed}
+}
+
+func (s *amqpSession) NotifyClose() <-chan *amqp.Error {
+	return s.closed
+}
+
+// Close closes the connection, which closes the channel with it.
+func (s *amqpSession) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,96 @@
+package rabbitmq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+type SessionStub struct {
+	Broker string
+	closed chan *amqp.Error
+
+	mu        sync.Mutex
+	published int
+}
+
+func newSessionStub(broker string) *SessionStub {
+	return &SessionStub{Broker: broker, closed: make(chan *amqp.Error, 1)}
+}
+
+func (s *SessionStub) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.published++
+	return nil
+}
+
+func (s *SessionStub) NotifyClose() <-chan *amqp.Error {
+	return s.closed
+}
+
+func (s *SessionStub) Close() error {
+	return nil
+}
+
+func (s *SessionStub) Published() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.published
+}
+
+func TestGivenALostConnection_WhenPublishing_ThenShouldReconnectToTheNextBroker(t *testing.T) {
+	first, second := newSessionStub("first"), newSessionStub("second")
+	sessions := make(chan session, 2)
+	sessions <- first
+	sessions <- second
+	publisher := NewPublisher(newTestConnector(&DialerStub{}, &bytes.Buffer{}))
+	publisher.open = func(ctx context.Context) (session, error) {
+		select {
+		case s := <-sessions:
+			return s, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	assert.Nil(t, publisher.Start(context.Background()))
+	defer publisher.Close()
+
+	assert.Nil(t, publisher.Publish("amq.direct", "", false, false, amqp.Publishing{}))
+	first.closed <- amqp.ErrClosed
+
+	assert.Eventually(t, func() bool {
+		publisher.Publish("amq.direct", "", false, false, amqp.Publishing{})
+		return second.Published() > 0
+	}, time.Second, time.Millisecond)
+	before := first.Published()
+	assert.Nil(t, publisher.Publish("amq.direct", "", false, false, amqp.Publishing{}))
+	assert.Equal(t, before, first.Published())
+}
+
+func TestGivenABrokerThatIsStillDown_WhenPublishing_ThenShouldReturnErrNotConnected(t *testing.T) {
+	first := newSessionStub("first")
+	opened := 0
+	publisher := NewPublisher(newTestConnector(&DialerStub{}, &bytes.Buffer{}))
+	publisher.open = func(ctx context.Context) (session, error) {
+		opened++
+		if opened == 1 {
+			return first, nil
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	assert.Nil(t, publisher.Start(context.Background()))
+	first.closed <- amqp.ErrClosed
+
+	assert.Eventually(t, func() bool {
+		return errors.Is(publisher.Publish("amq.direct", "", false, false, amqp.Publishing{}), ErrNotConnected)
+	}, time.Second, time.Millisecond)
+	assert.Nil(t, publisher.Close())
+}
@@ -0,0 +1,46 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+)
+
+type WebArchiveHandler struct {
+	OrderArchiver   entity.OrderArchiver
+	EventDispatcher events.EventDispatcherInterface
+	// MaxAge and BatchSize configure the archiving use case, set from
+	// ORDER_ARCHIVE_MAX_AGE and ORDER_ARCHIVE_BATCH_SIZE.
+	MaxAge    time.Duration
+	BatchSize int
+	// Response writes the JSON bodies and error envelopes.
+	Response response.Writer
+}
+
+func NewWebArchiveHandler(archiver entity.OrderArchiver, dispatcher events.EventDispatcherInterface) *WebArchiveHandler {
+	return &WebArchiveHandler{
+		OrderArchiver:   archiver,
+		EventDispatcher: dispatcher,
+		MaxAge:          usecase.DefaultArchiveMaxAge,
+		BatchSize:       usecase.DefaultArchiveBatchSize,
+	}
+}
+
+// Archive moves the orders older than ORDER_ARCHIVE_MAX_AGE to the archive.
+func (h *WebArchiveHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	archiveOrders := usecase.NewArchiveOrdersUseCase(h.OrderArchiver, event.NewOrdersArchived(), h.EventDispatcher)
+	archiveOrders.MaxAge = h.MaxAge
+	archiveOrders.BatchSize = h.BatchSize
+	output, err := archiveOrders.Execute(r.Context())
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
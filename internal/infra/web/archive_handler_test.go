@@ -0,0 +1,56 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/webserver"
+	"github.com/stretchr/testify/assert"
+)
+
+type archiverStub struct {
+	archived int
+	err      error
+}
+
+func (a *archiverStub) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int, archivedAt time.Time) (int, error) {
+	return a.archived, a.err
+}
+
+func TestGivenOldOrders_WhenArchive_ThenShouldReportHowManyMoved(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	NewWebArchiveHandler(&archiverStub{archived: 3}, nil).Archive(rec, httptest.NewRequest(http.MethodPost, "/admin/orders/archive", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Archived int `json:"archived"`
+		Batches  int `json:"batches"`
+	}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, 3, body.Archived)
+	assert.Equal(t, 1, body.Batches)
+}
+
+func TestGivenAFailingArchiver_WhenArchive_ThenShouldRespondInternalError(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	NewWebArchiveHandler(&archiverStub{err: errors.New("connection refused")}, nil).Archive(rec, httptest.NewRequest(http.MethodPost, "/admin/orders/archive", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestGivenNoBodyAndNoContentType_WhenPostingToArchiveThroughTheRouter_ThenShouldArchive(t *testing.T) {
+	server := webserver.NewWebServer(":0")
+	server.AddHandler(http.MethodPost, "/admin/orders/archive", NewWebArchiveHandler(&archiverStub{archived: 1}, nil).Archive)
+	rec := httptest.NewRecorder()
+
+	server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/orders/archive", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
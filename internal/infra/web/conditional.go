@@ -0,0 +1,60 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+// listETag hashes the page about to be sent, so any change to its orders
+// changes it, even two updates within the second updated_at is stored to. It
+// is weak because the hash covers the page's content, not its exact bytes.
+func listETag(page interface{}) (string, error) {
+	content, err := json.Marshal(page)
+	if err != nil {
+		return "", err
+	}
+	hash := fnv.New64a()
+	hash.Write(content)
+	return fmt.Sprintf(`W/"%x"`, hash.Sum64()), nil
+}
+
+// setLastModified sets Last-Modified from version. If-Modified-Since is never
+// answered from it: deleting or archiving an order changes the list without
+// moving the latest updated_at, so only the ETag decides on 304 Not Modified.
+func setLastModified(w http.ResponseWriter, version entity.OrderSetVersion) {
+	if version.LastUpdatedAt.IsZero() {
+		return
+	}
+	w.Header().Set("Last-Modified", version.LastUpdatedAt.UTC().Format(http.TimeFormat))
+}
+
+// writeETag sets the ETag of page. When it matches the request's
+// If-None-Match it writes 304 Not Modified and returns true.
+func writeETag(w http.ResponseWriter, r *http.Request, page interface{}) (bool, error) {
+	etag, err := listETag(page)
+	if err != nil {
+		return false, err
+	}
+	w.Header().Set("ETag", etag)
+	if inm := r.Header.Get("If-None-Match"); inm == "" || !etagMatches(inm, etag) {
+		return false, nil
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true, nil
+}
+
+// etagMatches applies the weak comparison If-None-Match uses.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+)
+
+// errNotABatch is returned by decodeBatch for a body that is not an object.
+var errNotABatch = errors.New(`invalid JSON: expected an object with an "orders" array`)
+
+// decodeBatch decodes a CreateOrdersBatchInputDTO one order at a time and
+// stops with entity.ErrBatchTooLarge as soon as it reads more than max orders,
+// so an oversized batch is rejected without decoding the rest of it. Zero max
+// means unlimited.
+func decodeBatch(body io.Reader, max int) (usecase.CreateOrdersBatchInputDTO, error) {
+	var dto usecase.CreateOrdersBatchInputDTO
+	dec := json.NewDecoder(body)
+	token, err := dec.Token()
+	if err != nil {
+		return dto, err
+	}
+	if token != json.Delim('{') {
+		return dto, errNotABatch
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return dto, err
+		}
+		if key != "orders" {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return dto, err
+			}
+			continue
+		}
+		if dto.Orders, err = decodeBatchOrders(dec, max); err != nil {
+			return dto, err
+		}
+	}
+	_, err = dec.Token()
+	return dto, err
+}
+
+func decodeBatchOrders(dec *json.Decoder, max int) ([]usecase.OrderInputDTO, error) {
+	token, err := dec.Token()
+	if err != nil || token == nil {
+		return nil, err
+	}
+	if token != json.Delim('[') {
+		return nil, &json.UnmarshalTypeError{Value: fmt.Sprint(token), Type: reflect.TypeFor[[]usecase.OrderInputDTO](), Offset: dec.InputOffset(), Field: "orders"}
+	}
+	orders := []usecase.OrderInputDTO{}
+	for dec.More() {
+		if max > 0 && len(orders) == max {
+			return nil, fmt.Errorf("%w: the maximum is %d", entity.ErrBatchTooLarge, max)
+		}
+		var order usecase.OrderInputDTO
+		if err := dec.Decode(&order); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) && typeErr.Field != "" {
+				typeErr.Field = "orders." + typeErr.Field
+			}
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	_, err = dec.Token()
+	return orders, err
+}
+
+// describeDecodeError turns a failed JSON decode of a request body into a
+// message telling the client where the body went wrong: the byte offset of a
+// syntax error, or the field and offset of a value of the wrong type.
+func describeDecodeError(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("invalid JSON at offset %d: %s", syntaxErr.Offset, syntaxErr.Error())
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return fmt.Sprintf("invalid value for field %q at offset %d: expected %s, got %s", typeErr.Field, typeErr.Offset, jsonKind(typeErr.Type), typeErr.Value)
+	case errors.As(err, &typeErr):
+		return fmt.Sprintf("invalid JSON at offset %d: expected %s, got %s", typeErr.Offset, jsonKind(typeErr.Type), typeErr.Value)
+	case errors.Is(err, io.EOF):
+		return "request body is empty"
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "invalid JSON: unexpected end of input"
+	}
+	return err.Error()
+}
+
+// jsonKind names the JSON value a Go type decodes from.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
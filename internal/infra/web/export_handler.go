@@ -0,0 +1,55 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+)
+
+type WebExportHandler struct {
+	OrderExporter entity.OrderExporter
+	// TimestampFormat lays out the exported timestamps, from JSON_TIME_FORMAT.
+	TimestampFormat string
+	// Response writes the JSON bodies and error envelopes.
+	Response response.Writer
+}
+
+func NewWebExportHandler(exporter entity.OrderExporter) *WebExportHandler {
+	return &WebExportHandler{OrderExporter: exporter}
+}
+
+// Export streams every order as newline-delimited JSON. ?fields is a comma
+// separated list of the fields to include; all of them are written without it.
+// A failure before the first line is answered with 500. Once a line is sent
+// the status cannot change, so later failures are only logged and end the
+// stream early.
+func (h *WebExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var input usecase.ExportOrdersInputDTO
+	if value := r.URL.Query().Get("fields"); value != "" {
+		for _, field := range strings.Split(value, ",") {
+			input.Fields = append(input.Fields, strings.TrimSpace(field))
+		}
+	}
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	out := newFlushWriter(w)
+	exportOrders := usecase.NewExportOrdersUseCase(h.OrderExporter)
+	exportOrders.TimestampFormat = h.TimestampFormat
+	err := exportOrders.Execute(r.Context(), input, out)
+	if err == nil {
+		return
+	}
+	switch {
+	case errors.Is(err, entity.ErrInvalidExportField):
+		h.Response.BadRequest(w, err.Error(), err)
+	case !out.written:
+		h.Response.InternalError(w, err)
+	default:
+		slog.Error("order export failed", "error", err)
+	}
+}
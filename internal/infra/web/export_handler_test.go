@@ -0,0 +1,58 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type exporterStub struct {
+	fields []string
+	err    error
+}
+
+func (e *exporterStub) Export(ctx context.Context, w io.Writer, timeLayout string, fields ...string) error {
+	e.fields = fields
+	if e.err != nil {
+		return e.err
+	}
+	_, err := io.WriteString(w, "{\"id\":\"order-0\"}\n")
+	return err
+}
+
+func TestGivenFields_WhenExport_ThenShouldStreamOnlyThoseFields(t *testing.T) {
+	exporter := &exporterStub{}
+	rec := httptest.NewRecorder()
+
+	NewWebExportHandler(exporter).Export(rec, httptest.NewRequest(http.MethodGet, "/orders/export?fields=id,%20final_price", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Equal(t, []string{"id", "final_price"}, exporter.fields)
+}
+
+func TestGivenAnUnknownField_WhenExport_ThenShouldRespondBadRequest(t *testing.T) {
+	exporter := &exporterStub{}
+	rec := httptest.NewRecorder()
+
+	NewWebExportHandler(exporter).Export(rec, httptest.NewRequest(http.MethodGet, "/orders/export?fields=id,secret", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `unknown field \"secret\"`)
+	assert.Nil(t, exporter.fields)
+}
+
+func TestGivenAQueryFailure_WhenExport_ThenShouldRespondInternalError(t *testing.T) {
+	exporter := &exporterStub{err: errors.New("connection refused")}
+	rec := httptest.NewRecorder()
+
+	NewWebExportHandler(exporter).Export(rec, httptest.NewRequest(http.MethodGet, "/orders/export", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"INTERNAL_ERROR"`)
+}
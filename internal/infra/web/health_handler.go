@@ -0,0 +1,39 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/database"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+)
+
+type WebHealthHandler struct {
+	Checker *database.HealthChecker
+	// Response writes the JSON bodies and error envelopes.
+	Response response.Writer
+}
+
+func NewWebHealthHandler(checker *database.HealthChecker) *WebHealthHandler {
+	return &WebHealthHandler{Checker: checker}
+}
+
+// Health reports the cached database health: 200 when it is reachable, 503
+// otherwise.
+func (h *WebHealthHandler) Health(w http.ResponseWriter, r *http.Request) {
+	h.writeHealth(w, h.Checker.Check(r.Context()))
+}
+
+// Recheck pings the database immediately and refreshes the cached status.
+func (h *WebHealthHandler) Recheck(w http.ResponseWriter, r *http.Request) {
+	h.writeHealth(w, h.Checker.Recheck(r.Context()))
+}
+
+func (h *WebHealthHandler) writeHealth(w http.ResponseWriter, status database.HealthStatus) {
+	if !status.Healthy() {
+		slog.Warn("health check failed", "error", status.Err)
+		h.Response.JSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	h.Response.JSON(w, http.StatusOK, status)
+}
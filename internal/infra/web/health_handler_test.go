@@ -0,0 +1,26 @@
+package web
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/database"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/webserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenNoBodyAndNoContentType_WhenPostingToRecheckThroughTheRouter_ThenShouldPingTheDatabase(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	defer db.Close()
+	server := webserver.NewWebServer(":0")
+	server.AddHandler(http.MethodPost, "/admin/health/recheck", NewWebHealthHandler(database.NewHealthChecker(db, time.Minute)).Recheck)
+	rec := httptest.NewRecorder()
+
+	server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/health/recheck", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,30 @@
+package web
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/database"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+)
+
+type WebMigrationHandler struct {
+	Db *sql.DB
+	// Response writes the JSON bodies and error envelopes.
+	Response response.Writer
+}
+
+func NewWebMigrationHandler(db *sql.DB) *WebMigrationHandler {
+	return &WebMigrationHandler{Db: db}
+}
+
+// List reports the schema version and the migrations applied to reach it.
+func (h *WebMigrationHandler) List(w http.ResponseWriter, r *http.Request) {
+	status, err := database.ReadMigrationStatus(r.Context(), h.Db)
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, status)
+}
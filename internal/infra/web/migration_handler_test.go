@@ -0,0 +1,42 @@
+package web
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+
+	// migrate sqlite3 driver
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+)
+
+func TestGivenAMigratedDatabase_WhenListMigrations_ThenShouldReturnTheAppliedVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.db")
+	migrator, err := database.NewMigrator("sqlite3://" + path)
+	assert.Nil(t, err)
+	defer migrator.Close()
+	assert.Nil(t, migrator.Migrate(3))
+	db, err := sql.Open("sqlite3", path)
+	assert.Nil(t, err)
+	defer db.Close()
+	rec := httptest.NewRecorder()
+
+	NewWebMigrationHandler(db).List(rec, httptest.NewRequest(http.MethodGet, "/admin/migrations", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var status database.MigrationStatus
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&status))
+	assert.Equal(t, database.MigrationStatus{
+		Version: 3,
+		Applied: []database.MigrationInfo{
+			{Version: 1, Name: "create_orders_table"},
+			{Version: 2, Name: "add_status_to_orders"},
+			{Version: 3, Name: "add_timestamps_to_orders"},
+		},
+	}, status)
+}
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// acceptsNDJSON reports whether the Accept header lists application/x-ndjson
+// with a non-zero quality.
+func acceptsNDJSON(r *http.Request) bool {
+	for _, value := range r.Header.Values("Accept") {
+		for _, entry := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+			if err == nil && mediaType == ndjsonContentType && params["q"] != "0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flushWriter flushes the response after every Write, so each line of a
+// stream reaches the client as soon as it is written.
+type flushWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	written    bool
+}
+
+func newFlushWriter(w http.ResponseWriter) *flushWriter {
+	return &flushWriter{w: w, controller: http.NewResponseController(w)}
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.written = f.written || n > 0
+	if err != nil {
+		return n, err
+	}
+	// Writers that cannot flush still deliver the stream, only buffered.
+	if err := f.controller.Flush(); err != nil && err != http.ErrNotSupported {
+		return n, err
+	}
+	return n, nil
+}
@@ -2,17 +2,57 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
 	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
 	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/mvr-garcia/go-clean-arch/pkg/pagination"
 )
 
 type WebOrderHandler struct {
 	EventDispatcher   events.EventDispatcherInterface
 	OrderRepository   entity.OrderRepositoryInterface
 	OrderCreatedEvent events.EventInterface
+	BatchAllOrNothing bool
+	// MaxBatchSize is the largest batch POST /orders/batch accepts, set from
+	// BATCH_MAX_SIZE. Zero means unlimited.
+	MaxBatchSize int
+	// ListCacheMaxAge is advertised as Cache-Control max-age on GET /order.
+	// Zero disables caching.
+	ListCacheMaxAge time.Duration
+	// ListSnapshot makes GET /order read from a snapshot even when the request
+	// sets neither ?snapshot nor ?as_of.
+	ListSnapshot bool
+	// ListConditional adds ETag and Last-Modified to GET /order and answers
+	// matching If-None-Match or If-Modified-Since with 304 Not Modified.
+	ListConditional bool
+	// OrderIterator, when set, streams every order as NDJSON to GET /order
+	// requests that accept application/x-ndjson.
+	OrderIterator entity.OrderIterator
+	// Pagination bounds the page size of every listing endpoint.
+	Pagination pagination.Limits
+	// OrderRules are set on every use case that creates or previews orders,
+	// built from ORDER_RULES.
+	OrderRules []entity.OrderRule
+	// TimestampFormat is set on every use case whose output has timestamps,
+	// from JSON_TIME_FORMAT.
+	TimestampFormat string
+	// MaxOrderIDLength bounds the order IDs every endpoint accepts, set from
+	// ORDER_ID_MAX_LENGTH. Zero means entity.MaxOrderIDLength.
+	MaxOrderIDLength int
+	// Response writes the JSON bodies and error envelopes.
+	Response response.Writer
 }
 
 func NewWebOrderHandler(
@@ -24,6 +64,7 @@ func NewWebOrderHandler(
 		EventDispatcher:   EventDispatcher,
 		OrderRepository:   OrderRepository,
 		OrderCreatedEvent: OrderCreatedEvent,
+		BatchAllOrNothing: true,
 	}
 }
 
@@ -31,35 +72,463 @@ func (h *WebOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var dto usecase.OrderInputDTO
 	err := json.NewDecoder(r.Body).Decode(&dto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		h.Response.BadRequest(w, describeDecodeError(err), err)
 		return
 	}
+	if dto.ID, err = entity.NormalizeOrderID(dto.ID, h.MaxOrderIDLength); err != nil {
+		h.Response.BadRequest(w, err.Error(), err)
+		return
+	}
+	dto.TraceID = middleware.GetReqID(r.Context())
 
 	createOrder := usecase.NewCreateOrderUseCase(h.OrderRepository, h.OrderCreatedEvent, h.EventDispatcher)
-	output, err := createOrder.Execute(dto)
+	createOrder.TimestampFormat = h.TimestampFormat
+	createOrder.Rules = h.OrderRules
+	output, err := createOrder.Execute(r.Context(), dto)
+	if err != nil {
+		h.writeCreateError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// Preview computes the final price an order would get without creating it.
+func (h *WebOrderHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var dto usecase.OrderPreviewInputDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.Response.BadRequest(w, describeDecodeError(err), err)
+		return
+	}
+
+	preview := usecase.NewCalculateOrderPreviewUseCase()
+	preview.Rules = h.OrderRules
+	output, err := preview.Execute(dto)
+	if err != nil {
+		h.writeCreateError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+func (h *WebOrderHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	dto, err := decodeBatch(r.Body, h.MaxBatchSize)
+	if errors.Is(err, entity.ErrBatchTooLarge) {
+		h.Response.Error(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", err.Error(), err)
+		return
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.Response.BadRequest(w, describeDecodeError(err), err)
+		return
+	}
+	traceID := middleware.GetReqID(r.Context())
+	for i := range dto.Orders {
+		if dto.Orders[i].ID, err = entity.NormalizeOrderID(dto.Orders[i].ID, h.MaxOrderIDLength); err != nil {
+			h.Response.BadRequest(w, fmt.Sprintf("item %d: %s", i, err), err)
+			return
+		}
+		dto.Orders[i].TraceID = traceID
+	}
+
+	createBatch := usecase.NewCreateOrdersBatchUseCase(h.OrderRepository, h.OrderCreatedEvent, h.EventDispatcher, h.BatchAllOrNothing)
+	createBatch.TimestampFormat = h.TimestampFormat
+	createBatch.MaxBatchSize = h.MaxBatchSize
+	createBatch.Rules = h.OrderRules
+	output, err := createBatch.Execute(r.Context(), dto)
+	if err != nil {
+		if errors.Is(err, entity.ErrBatchTooLarge) {
+			h.Response.Error(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", err.Error(), err)
+			return
+		}
+		// A *entity.BatchItemError wraps either a broken rule or the storage
+		// failure of that item, so it is mapped by what it wraps.
+		h.writeCreateError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// Duplicate copies the order in the {id} path parameter into a new order. The
+// body may set the new order's "id"; an empty body gets a generated ID.
+func (h *WebOrderHandler) Duplicate(w http.ResponseWriter, r *http.Request) {
+	var dto usecase.DuplicateOrderInputDTO
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil && !errors.Is(err, io.EOF) {
+		h.Response.BadRequest(w, describeDecodeError(err), err)
+		return
+	}
+	if dto.SourceID, err = entity.NormalizeOrderID(chi.URLParam(r, "id"), h.MaxOrderIDLength); err != nil {
+		h.Response.BadRequest(w, err.Error(), err)
 		return
 	}
+	if dto.ID != "" {
+		if dto.ID, err = entity.NormalizeOrderID(dto.ID, h.MaxOrderIDLength); err != nil {
+			h.Response.BadRequest(w, err.Error(), err)
+			return
+		}
+	}
+	dto.TraceID = middleware.GetReqID(r.Context())
 
-	err = json.NewEncoder(w).Encode(output)
+	duplicateOrder := usecase.NewDuplicateOrderUseCase(h.OrderRepository, h.OrderCreatedEvent, h.EventDispatcher)
+	duplicateOrder.CreateOrder.Rules = h.OrderRules
+	duplicateOrder.CreateOrder.TimestampFormat = h.TimestampFormat
+	output, err := duplicateOrder.Execute(r.Context(), dto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, entity.ErrOrderNotFound) {
+			h.Response.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error(), err)
+			return
+		}
+		h.writeCreateError(w, err)
 		return
 	}
+
+	h.Response.JSON(w, http.StatusCreated, output)
+}
+
+// History returns the audit trail of the order in the {id} path parameter.
+func (h *WebOrderHandler) History(w http.ResponseWriter, r *http.Request) {
+	getOrderHistory := usecase.NewGetOrderHistoryUseCase(h.OrderRepository)
+	getOrderHistory.TimestampFormat = h.TimestampFormat
+	id, err := entity.NormalizeOrderID(chi.URLParam(r, "id"), h.MaxOrderIDLength)
+	if err != nil {
+		h.Response.BadRequest(w, err.Error(), err)
+		return
+	}
+	output, err := getOrderHistory.Execute(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, entity.ErrOrderNotFound) {
+			h.Response.Error(w, http.StatusNotFound, "NOT_FOUND", err.Error(), err)
+			return
+		}
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
 }
 
 func (h *WebOrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	if h.OrderIterator != nil {
+		w.Header().Add("Vary", "Accept")
+		if acceptsNDJSON(r) {
+			h.streamList(w, r)
+			return
+		}
+	}
+
+	page, err := h.parsePagination(r)
+	if err != nil {
+		h.Response.BadRequest(w, err.Error(), err)
+		return
+	}
+
+	snapshot, asOf, err := h.parseSnapshot(r)
+	if err != nil {
+		h.Response.BadRequest(w, err.Error(), err)
+		return
+	}
+
+	withTotal := false
+	if value := r.URL.Query().Get("total"); value != "" {
+		withTotal, err = strconv.ParseBool(value)
+		if err != nil {
+			h.Response.BadRequest(w, "invalid total", err)
+			return
+		}
+	}
+
 	listOrders := usecase.NewListOrdersUseCase(h.OrderRepository)
-	output, err := listOrders.Execute()
+	listOrders.TimestampFormat = h.TimestampFormat
+	if h.ListConditional {
+		version, err := listOrders.Version(r.Context())
+		if err != nil {
+			h.Response.InternalError(w, err)
+			return
+		}
+		setLastModified(w, version)
+	}
+
+	var output usecase.ListOrdersOutputDTO
+	switch {
+	case snapshot && withTotal:
+		h.Response.BadRequest(w, "total is not available on snapshots", nil)
+		return
+	case snapshot:
+		output, err = listOrders.ExecuteAsOf(r.Context(), page, asOf)
+	case withTotal:
+		output, err = listOrders.ExecuteWithTotal(r.Context(), page)
+	default:
+		output, err = listOrders.Execute(r.Context(), page)
+	}
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.setListCacheControl(w)
+	if h.ListConditional {
+		notModified, err := writeETag(w, r, output)
+		if err != nil {
+			h.Response.InternalError(w, err)
+			return
+		}
+		if notModified {
+			return
+		}
+	}
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// streamList writes every order as NDJSON, flushing after each one, so the
+// list does not have to be loaded before the client gets the first order.
+// Pagination, snapshots and totals do not apply. A failure before the first
+// order is a 500; after it the status is already sent, so it is only logged
+// and ends the stream early.
+func (h *WebOrderHandler) streamList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	out := newFlushWriter(w)
+	streamOrders := usecase.NewStreamOrdersUseCase(h.OrderIterator)
+	streamOrders.TimestampFormat = h.TimestampFormat
+	err := streamOrders.Execute(r.Context(), out)
+	if err == nil {
+		return
+	}
+	if !out.written {
+		h.Response.InternalError(w, err)
+		return
+	}
+	slog.Error("order stream failed", "error", err)
+}
+
+func (h *WebOrderHandler) setListCacheControl(w http.ResponseWriter) {
+	if h.ListCacheMaxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(h.ListCacheMaxAge.Seconds())))
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+}
+
+func (h *WebOrderHandler) ListByStatus(w http.ResponseWriter, r *http.Request) {
+	sample := 0
+	if value := r.URL.Query().Get("sample"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			h.Response.BadRequest(w, "invalid sample", err)
+			return
+		}
+		if parsed > h.Pagination.MaxLimit() {
+			h.Response.BadRequest(w, fmt.Sprintf("sample must not exceed %d", h.Pagination.MaxLimit()), nil)
+			return
+		}
+		sample = parsed
+	}
+
+	listOrdersByStatus := usecase.NewListOrdersByStatusUseCase(h.OrderRepository)
+	listOrdersByStatus.TimestampFormat = h.TimestampFormat
+	output, err := listOrdersByStatus.Execute(r.Context(), sample)
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+func (h *WebOrderHandler) ListRecent(w http.ResponseWriter, r *http.Request) {
+	n := 0
+	if value := r.URL.Query().Get("n"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			h.Response.BadRequest(w, "invalid n", err)
+			return
+		}
+		n = parsed
+	}
+
+	listRecentOrders := usecase.NewListRecentOrdersUseCase(h.OrderRepository)
+	listRecentOrders.TimestampFormat = h.TimestampFormat
+	output, err := listRecentOrders.Execute(r.Context(), n)
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// Top returns the order with the highest final price, or a null order when
+// there are none.
+func (h *WebOrderHandler) Top(w http.ResponseWriter, r *http.Request) {
+	getMostExpensiveOrder := usecase.NewGetMostExpensiveOrderUseCase(h.OrderRepository)
+	getMostExpensiveOrder.TimestampFormat = h.TimestampFormat
+	output, err := getMostExpensiveOrder.Execute(r.Context())
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// Changes lists the orders updated after ?since (RFC3339), least recently
+// updated first, limit at a time. Later pages are requested with the
+// next_cursor of the previous one as ?cursor instead of since.
+func (h *WebOrderHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	page, err := h.parsePagination(r)
+	if err != nil {
+		h.Response.BadRequest(w, err.Error(), err)
+		return
+	}
+	if page.Offset != 0 {
+		h.Response.BadRequest(w, "offset is not supported, page with cursor", nil)
+		return
+	}
+
+	var after entity.OrderChangeCursor
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		after, err = usecase.ParseOrderChangeCursor(value)
+		if err != nil {
+			h.Response.BadRequest(w, err.Error(), err)
+			return
+		}
+	} else {
+		after.UpdatedAt, err = time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+		if err != nil {
+			h.Response.BadRequest(w, "invalid since", err)
+			return
+		}
+	}
+
+	listOrderChanges := usecase.NewListOrderChangesUseCase(h.OrderRepository)
+	listOrderChanges.TimestampFormat = h.TimestampFormat
+	output, err := listOrderChanges.Execute(r.Context(), after, page.Limit)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.Response.InternalError(w, err)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(output)
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// Search looks orders up by ID prefix (?id_prefix). The number of results
+// follows the pagination limit; offset is ignored.
+func (h *WebOrderHandler) Search(w http.ResponseWriter, r *http.Request) {
+	page, err := h.parsePagination(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		h.Response.BadRequest(w, err.Error(), err)
 		return
 	}
+
+	searchOrders := usecase.NewSearchOrdersByIDPrefixUseCase(h.OrderRepository)
+	searchOrders.TimestampFormat = h.TimestampFormat
+	output, err := searchOrders.Execute(r.Context(), r.URL.Query().Get("id_prefix"), page.Limit)
+	if err != nil {
+		if errors.Is(err, entity.ErrIDPrefixTooShort) {
+			h.Response.BadRequest(w, err.Error(), err)
+			return
+		}
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// BulkUpdateStatus moves every order in from_status, optionally created before
+// created_before, to to_status.
+func (h *WebOrderHandler) BulkUpdateStatus(w http.ResponseWriter, r *http.Request) {
+	var dto usecase.BulkUpdateOrderStatusInputDTO
+	err := json.NewDecoder(r.Body).Decode(&dto)
+	if err != nil {
+		h.Response.BadRequest(w, describeDecodeError(err), err)
+		return
+	}
+
+	bulkUpdate := usecase.NewBulkUpdateOrderStatusUseCase(h.OrderRepository, event.NewOrdersStatusChanged(), h.EventDispatcher)
+	output, err := bulkUpdate.Execute(r.Context(), dto)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidStatusTransition) {
+			h.Response.Error(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", err.Error(), err)
+			return
+		}
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// AuditTotals reports orders whose stored final price does not match price + tax.
+func (h *WebOrderHandler) AuditTotals(w http.ResponseWriter, r *http.Request) {
+	auditOrderTotals := usecase.NewAuditOrderTotalsUseCase(h.OrderRepository)
+	output, err := auditOrderTotals.Execute(r.Context())
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
+
+// writeCreateError maps a failed create to its HTTP status: a broken business
+// rule is unprocessable, an existing order ID is a conflict and an unreachable
+// database is reported as unavailable.
+func (h *WebOrderHandler) writeCreateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrDomain) {
+		h.Response.Error(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", err.Error(), err)
+		return
+	}
+	switch {
+	case errors.Is(err, entity.ErrOrderAlreadyExists):
+		h.Response.Error(w, http.StatusConflict, "CONFLICT", "order already exists", err)
+	case errors.Is(err, entity.ErrStorageUnavailable):
+		h.Response.Error(w, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "database unavailable", err)
+	default:
+		h.Response.InternalError(w, err)
+	}
+}
+
+// parseSnapshot reports whether the list should be read from a snapshot and
+// which one: ?as_of (RFC3339) continues an existing snapshot, ?snapshot=true or
+// ListSnapshot starts a new one.
+func (h *WebOrderHandler) parseSnapshot(r *http.Request) (bool, time.Time, error) {
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		asOf, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return false, time.Time{}, errors.New("invalid as_of")
+		}
+		return true, asOf, nil
+	}
+	if value := r.URL.Query().Get("snapshot"); value != "" {
+		snapshot, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, time.Time{}, errors.New("invalid snapshot")
+		}
+		return snapshot, time.Time{}, nil
+	}
+	return h.ListSnapshot, time.Time{}, nil
+}
+
+// parsePagination reads the limit and offset query parameters. Missing values
+// are left to the h.Pagination defaults.
+func (h *WebOrderHandler) parsePagination(r *http.Request) (pagination.Pagination, error) {
+	var limit, offset int
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return pagination.Pagination{}, errors.New("invalid limit")
+		}
+		limit = parsed
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return pagination.Pagination{}, errors.New("invalid offset")
+		}
+		offset = parsed
+	}
+	return h.Pagination.Parse(limit, offset)
 }
@@ -0,0 +1,556 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/mvr-garcia/go-clean-arch/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestHandler(repository *mocks.OrderRepositoryMock) *WebOrderHandler {
+	return NewWebOrderHandler(events.NewNoopDispatcher(), repository, event.NewOrderCreated())
+}
+
+func TestGivenNoCacheMaxAge_WhenList_ThenShouldSendNoCache(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{}, nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).List(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+}
+
+func TestGivenACacheMaxAge_WhenList_ThenShouldAdvertiseIt(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{}, nil)
+	handler := newTestHandler(repository)
+	handler.ListCacheMaxAge = 30 * time.Second
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "max-age=30", rec.Header().Get("Cache-Control"))
+}
+
+func TestGivenATimestampFormat_WhenList_ThenShouldLayOutTheTimestampsWithIt(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{{ID: "a", CreatedAt: createdAt, UpdatedAt: createdAt}}, nil)
+	handler := newTestHandler(repository)
+	handler.TimestampFormat = "2006-01-02 15:04:05"
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"created_at":"2024-05-01 12:30:00"`)
+}
+
+func TestGivenAShortPrefix_WhenSearch_ThenShouldRespondBadRequest(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("SearchByIDPrefix", mock.Anything, "a", pagination.DefaultLimit).Return([]entity.Order(nil), entity.ErrIDPrefixTooShort)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Search(rec, httptest.NewRequest(http.MethodGet, "/orders/search?id_prefix=a", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "id prefix is too short")
+}
+
+func TestGivenPaginationParams_WhenList_ThenShouldClampAndForwardThem(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, pagination.MaxLimit, 10).Return([]entity.Order{}, nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).List(rec, httptest.NewRequest(http.MethodGet, "/order?limit=500&offset=10", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repository.AssertExpectations(t)
+}
+
+func TestGivenHandlerPaginationLimits_WhenList_ThenShouldApplyThem(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, 10, 0).Return([]entity.Order{}, nil)
+	handler := newTestHandler(repository)
+	handler.Pagination = pagination.Limits{Default: 5, Max: 10}
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, httptest.NewRequest(http.MethodGet, "/order?limit=50", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	repository.AssertExpectations(t)
+}
+
+func TestGivenASampleAboveTheMaxPageSize_WhenListByStatus_ThenShouldRespondBadRequest(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	handler := newTestHandler(repository)
+	handler.Pagination = pagination.Limits{Max: 10}
+	rec := httptest.NewRecorder()
+
+	handler.ListByStatus(rec, httptest.NewRequest(http.MethodGet, "/orders/by-status?sample=11", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "sample must not exceed 10")
+	repository.AssertNotCalled(t, "CountByStatus", mock.Anything)
+}
+
+func TestGivenANegativeOffset_WhenList_ThenShouldRespondBadRequest(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).List(rec, httptest.NewRequest(http.MethodGet, "/order?offset=-1", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	repository.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGivenADuplicateOrderID_WhenCreate_ThenShouldRespondConflict(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: %w", entity.ErrOrderAlreadyExists, errors.New("Duplicate entry '123' for key 'PRIMARY'")))
+	req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"123","price":10,"tax":2}`))
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Create(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"CONFLICT"`)
+}
+
+func TestGivenMalformedJSON_WhenCreate_ThenShouldRespondWithTheOffset(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"123","price":}`))
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Create(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var body response.ErrorEnvelope
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "invalid JSON at offset 21: invalid character '}' looking for beginning of value", body.Error.Message)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestGivenAFieldOfTheWrongType_WhenCreate_ThenShouldRespondWithTheFieldAndOffset(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"123","price":"10","tax":2}`))
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Create(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var body response.ErrorEnvelope
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, `invalid value for field "price" at offset 24: expected number, got string`, body.Error.Message)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestGivenAMissingSourceOrder_WhenDuplicate_ThenShouldRespondNotFound(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindByID", mock.Anything, "missing").Return(nil, entity.ErrOrderNotFound)
+	router := chi.NewRouter()
+	router.Post("/orders/{id}/duplicate", newTestHandler(repository).Duplicate)
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders/missing/duplicate", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"NOT_FOUND"`)
+}
+
+func TestGivenOrders_WhenTop_ThenShouldReturnTheMostExpensiveOne(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindMostExpensive", mock.Anything).Return(&entity.Order{ID: "top", Price: 100, Tax: 10, FinalPrice: 110, Status: entity.OrderStatusPending}, nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Top(rec, httptest.NewRequest(http.MethodGet, "/orders/top", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"order":{"id":"top"`)
+}
+
+func TestGivenNoOrders_WhenTop_ThenShouldReturnANullOrder(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindMostExpensive", mock.Anything).Return(nil, entity.ErrOrderNotFound)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Top(rec, httptest.NewRequest(http.MethodGet, "/orders/top", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"order":null}`, rec.Body.String())
+}
+
+func TestGivenNoOrders_WhenList_ThenShouldReturnAnEmptyArray(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order(nil), nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).List(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+	assert.JSONEq(t, `{"orders":[]}`, rec.Body.String())
+}
+
+func TestGivenAnAsOf_WhenList_ThenShouldReadFromThatSnapshot(t *testing.T) {
+	asOf := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPageAsOf", mock.Anything, asOf, pagination.DefaultLimit, 20).Return([]entity.Order{}, nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).List(rec, httptest.NewRequest(http.MethodGet, "/order?offset=20&as_of=2024-05-01T12:00:00Z", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"orders":[],"as_of":"2024-05-01T12:00:00Z"}`, rec.Body.String())
+	repository.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGivenTotalRequested_WhenList_ThenShouldIncludeTheTotal(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPageWithTotal", mock.Anything, pagination.DefaultLimit, 0).Return([]entity.Order{}, 42, nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).List(rec, httptest.NewRequest(http.MethodGet, "/order?total=true", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"orders":[],"total":42}`, rec.Body.String())
+	repository.AssertNotCalled(t, "FindPage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGivenAMissingSince_WhenChanges_ThenShouldRespondBadRequest(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Changes(rec, httptest.NewRequest(http.MethodGet, "/orders/changes", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid since")
+}
+
+func TestGivenANextCursor_WhenChanges_ThenShouldResumeAfterIt(t *testing.T) {
+	after := entity.OrderChangeCursor{UpdatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), ID: "a"}
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindUpdatedSince", mock.Anything, after, 1).Return([]entity.Order{{ID: "b", UpdatedAt: after.UpdatedAt}}, nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).Changes(rec, httptest.NewRequest(http.MethodGet, "/orders/changes?limit=1&cursor="+usecase.EncodeOrderChangeCursor(after), nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		NextCursor string `json:"next_cursor"`
+	}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, usecase.EncodeOrderChangeCursor(entity.OrderChangeCursor{UpdatedAt: after.UpdatedAt, ID: "b"}), body.NextCursor)
+}
+
+func TestGivenAnOffset_WhenChanges_ThenShouldRespondBadRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	newTestHandler(&mocks.OrderRepositoryMock{}).Changes(rec, httptest.NewRequest(http.MethodGet, "/orders/changes?since=2024-05-01T12:00:00Z&offset=20", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGivenABatchOverTheLimit_WhenCreateBatch_ThenShouldRejectItBeforeReadingTheRest(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	handler := newTestHandler(repository)
+	handler.MaxBatchSize = 1
+	// Everything after the second order is malformed; it must never be read.
+	body := `{"orders":[{"id":"a","price":10,"tax":2},{"id":"b","price":10,"tax":2},` + strings.Repeat("x", 1<<20)
+	rec := httptest.NewRecorder()
+
+	handler.CreateBatch(rec, httptest.NewRequest(http.MethodPost, "/orders/batch", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	repository.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything)
+}
+
+func TestGivenABatchItemOfTheWrongType_WhenCreateBatch_ThenShouldNameTheField(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	newTestHandler(&mocks.OrderRepositoryMock{}).CreateBatch(rec, httptest.NewRequest(http.MethodPost, "/orders/batch", strings.NewReader(`{"orders":[{"id":"a","price":"ten","tax":2}]}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `invalid value for field \"orders.price\"`)
+}
+
+func TestGivenABatchOverTheLimit_WhenCreateBatch_ThenShouldRespondPayloadTooLarge(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	handler := newTestHandler(repository)
+	handler.MaxBatchSize = 1
+	body := `{"orders":[{"id":"a","price":10,"tax":2},{"id":"b","price":10,"tax":2}]}`
+	rec := httptest.NewRecorder()
+
+	handler.CreateBatch(rec, httptest.NewRequest(http.MethodPost, "/orders/batch", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"PAYLOAD_TOO_LARGE"`)
+	repository.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything)
+}
+
+func TestGivenAnAllOrNothingBatch_WhenAnItemBreaksARule_ThenShouldRespondUnprocessable(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	handler := newTestHandler(repository)
+	handler.BatchAllOrNothing = true
+	rec := httptest.NewRecorder()
+
+	handler.CreateBatch(rec, httptest.NewRequest(http.MethodPost, "/orders/batch", strings.NewReader(`{"orders":[{"id":"a","price":10,"tax":2},{"id":"b","price":0,"tax":2}]}`)))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	repository.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything)
+}
+
+func TestGivenOrderRules_WhenCreateOrPreview_ThenShouldRejectViolationsWithoutSaving(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	handler := newTestHandler(repository)
+	handler.OrderRules = []entity.OrderRule{entity.TaxNotAbovePrice}
+
+	rec := httptest.NewRecorder()
+	handler.Create(rec, httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"a","price":10,"tax":20}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.Preview(rec, httptest.NewRequest(http.MethodPost, "/orders/preview", strings.NewReader(`{"price":10,"tax":20}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestGivenAnAllOrNothingBatch_WhenSavingAnItemFails_ThenShouldRespondInternalError(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("SaveBatch", mock.Anything, mock.Anything).Return(&entity.BatchItemError{Index: 1, Err: errors.New("disk full")})
+	handler := newTestHandler(repository)
+	handler.BatchAllOrNothing = true
+	rec := httptest.NewRecorder()
+
+	handler.CreateBatch(rec, httptest.NewRequest(http.MethodPost, "/orders/batch", strings.NewReader(`{"orders":[{"id":"a","price":10,"tax":2},{"id":"b","price":10,"tax":2}]}`)))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestGivenListConditional_WhenTheOrdersAreUnchanged_ThenShouldRespondNotModifiedUntilAnInsert(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Version", mock.Anything).Return(entity.OrderSetVersion{Count: 1, LastUpdatedAt: updatedAt}, nil).Twice()
+	repository.On("Version", mock.Anything).Return(entity.OrderSetVersion{Count: 2, LastUpdatedAt: updatedAt.Add(time.Minute)}, nil).Once()
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{{ID: "a", UpdatedAt: updatedAt}}, nil).Twice()
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{{ID: "a", UpdatedAt: updatedAt}, {ID: "b", UpdatedAt: updatedAt.Add(time.Minute)}}, nil).Once()
+	handler := newTestHandler(repository)
+	handler.ListConditional = true
+
+	rec := httptest.NewRecorder()
+	handler.List(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]+"$`, etag)
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", rec.Header().Get("Last-Modified"))
+
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.List(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.List(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	repository.AssertNumberOfCalls(t, "FindPage", 3)
+}
+
+func TestGivenListConditional_WhenAnOrderChangesWithinTheSameSecond_ThenShouldNotRespondNotModified(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Version", mock.Anything).Return(entity.OrderSetVersion{Count: 1, LastUpdatedAt: updatedAt}, nil)
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{{ID: "a", Status: entity.OrderStatusPending, UpdatedAt: updatedAt}}, nil).Once()
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{{ID: "a", Status: entity.OrderStatusProcessing, UpdatedAt: updatedAt}}, nil).Once()
+	handler := newTestHandler(repository)
+	handler.ListConditional = true
+
+	rec := httptest.NewRecorder()
+	handler.List(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	handler.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), entity.OrderStatusProcessing)
+}
+
+func TestGivenListConditional_WhenAnOrderIsDeletedBeforeIfModifiedSince_ThenShouldStillSendTheList(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	// The deleted order was not the latest one, so updated_at did not move.
+	repository.On("Version", mock.Anything).Return(entity.OrderSetVersion{Count: 1, LastUpdatedAt: updatedAt}, nil)
+	repository.On("FindPage", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order{{ID: "b", UpdatedAt: updatedAt}}, nil)
+	handler := newTestHandler(repository)
+	handler.ListConditional = true
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 12:00:00 GMT")
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":"b"`)
+}
+
+func TestGivenAnEmptyOrWhitespaceID_WhenCallingAnyOrderRoute_ThenShouldRespondBadRequest(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	router := chi.NewRouter()
+	handler := newTestHandler(repository)
+	router.Post("/order", handler.Create)
+	router.Post("/orders/batch", handler.CreateBatch)
+	router.Post("/orders/{id}/duplicate", handler.Duplicate)
+	router.Get("/order/{id}/history", handler.History)
+	requests := map[string]*http.Request{
+		"create with an empty id":        httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"","price":10,"tax":2}`)),
+		"create with a whitespace id":    httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"  ","price":10,"tax":2}`)),
+		"batch with a whitespace id":     httptest.NewRequest(http.MethodPost, "/orders/batch", strings.NewReader(`{"orders":[{"id":"\t","price":10,"tax":2}]}`)),
+		"duplicate of a whitespace id":   httptest.NewRequest(http.MethodPost, "/orders/%20/duplicate", nil),
+		"duplicate into a whitespace id": httptest.NewRequest(http.MethodPost, "/orders/a/duplicate", strings.NewReader(`{"id":" "}`)),
+		"history of a whitespace id":     httptest.NewRequest(http.MethodGet, "/order/%20%20/history", nil),
+	}
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "invalid id")
+		})
+	}
+	assert.Empty(t, repository.Calls)
+}
+
+// OrderIteratorStub yields Yield and then Err, if set. BeforeEach runs before
+// each order is yielded.
+type OrderIteratorStub struct {
+	Yield      []entity.Order
+	Err        error
+	BeforeEach func(i int)
+}
+
+func (s *OrderIteratorStub) Orders(ctx context.Context) iter.Seq2[entity.Order, error] {
+	return func(yield func(entity.Order, error) bool) {
+		for i, order := range s.Yield {
+			if s.BeforeEach != nil {
+				s.BeforeEach(i)
+			}
+			if !yield(order, nil) {
+				return
+			}
+		}
+		if s.Err != nil {
+			yield(entity.Order{}, s.Err)
+		}
+	}
+}
+
+func newNDJSONRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	return req
+}
+
+func TestGivenAnNDJSONAccept_WhenList_ThenShouldStreamOneOrderPerLineFlushingEachOne(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rec := httptest.NewRecorder()
+	var flushedBefore []string
+	iterator := &OrderIteratorStub{
+		Yield: []entity.Order{
+			{ID: "1", Price: 10, Tax: 2, Status: entity.OrderStatusPending, CreatedAt: created, UpdatedAt: created},
+			{ID: "2", Price: 0.1, Tax: 0.2, Status: entity.OrderStatusShipped, CreatedAt: created, UpdatedAt: created},
+		},
+		BeforeEach: func(i int) {
+			if i > 0 {
+				assert.True(t, rec.Flushed)
+				flushedBefore = append(flushedBefore, rec.Body.String())
+			}
+		},
+	}
+	handler := newTestHandler(&mocks.OrderRepositoryMock{})
+	handler.OrderIterator = iterator
+
+	handler.List(rec, newNDJSONRequest())
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	first := `{"id":"1","price":10,"tax":2,"final_price":12,"status":"pending","created_at":"2024-05-01T12:00:00Z","updated_at":"2024-05-01T12:00:00Z"}` + "\n"
+	second := `{"id":"2","price":0.1,"tax":0.2,"final_price":0.3,"status":"shipped","created_at":"2024-05-01T12:00:00Z","updated_at":"2024-05-01T12:00:00Z"}` + "\n"
+	assert.Equal(t, []string{first}, flushedBefore)
+	assert.Equal(t, first+second, rec.Body.String())
+}
+
+func TestGivenAnNDJSONStreamFailingMidway_WhenList_ThenShouldEndTheStreamAfterTheSentOrders(t *testing.T) {
+	handler := newTestHandler(&mocks.OrderRepositoryMock{})
+	handler.OrderIterator = &OrderIteratorStub{Yield: []entity.Order{{ID: "1", Price: 10, Tax: 2}}, Err: errors.New("connection reset")}
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, newNDJSONRequest())
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, strings.Count(rec.Body.String(), "\n"))
+	assert.Contains(t, rec.Body.String(), `"id":"1"`)
+}
+
+func TestGivenAnNDJSONStreamFailingBeforeTheFirstOrder_WhenList_ThenShouldRespondInternalError(t *testing.T) {
+	handler := newTestHandler(&mocks.OrderRepositoryMock{})
+	handler.OrderIterator = &OrderIteratorStub{Err: errors.New("connection refused")}
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, newNDJSONRequest())
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
+
+func TestGivenNDJSONEnabled_WhenListAcceptsJSON_ThenShouldReturnThePaginatedArray(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, pagination.DefaultLimit, 0).Return([]entity.Order{{ID: "1", Price: 10, Tax: 2}}, nil)
+	handler := newTestHandler(repository)
+	handler.OrderIterator = &OrderIteratorStub{}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set("Accept", "application/json, application/x-ndjson;q=0")
+
+	handler.List(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	assert.Contains(t, rec.Body.String(), `"orders":[{"id":"1"`)
+}
+
+func TestGivenNDJSONDisabled_WhenListAcceptsNDJSON_ThenShouldReturnThePaginatedArray(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, pagination.DefaultLimit, 0).Return([]entity.Order{}, nil)
+	rec := httptest.NewRecorder()
+
+	newTestHandler(repository).List(rec, newNDJSONRequest())
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Header().Get("Vary"))
+}
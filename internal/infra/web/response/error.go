@@ -0,0 +1,66 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+)
+
+// Writer writes JSON responses and the standard error envelope. The zero
+// value is ready to use: it never includes debug details, does not pool
+// encoding buffers and encodes everything through encoding/json.
+type Writer struct {
+	// Debug adds the underlying error and a stack trace to error responses.
+	// It must only be enabled in development.
+	Debug bool
+	// MaxPooledBufferSize is the largest encoding buffer, in bytes, returned
+	// to the pool after a response; bigger ones are left to the garbage
+	// collector so one huge list does not pin memory. Zero disables pooling.
+	MaxPooledBufferSize int
+	// UseAppenders makes JSON encode values implementing Appender through
+	// AppendJSON instead of encoding/json.
+	UseAppenders bool
+}
+
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string     `json:"code"`
+	Message string     `json:"message"`
+	Debug   *DebugInfo `json:"debug,omitempty"`
+}
+
+type DebugInfo struct {
+	Error string `json:"error,omitempty"`
+	Stack string `json:"stack"`
+}
+
+// Error writes the standard JSON error envelope. err is the underlying cause;
+// it is never sent to the client unless Debug is enabled.
+func (rw Writer) Error(w http.ResponseWriter, status int, code, message string, err error) {
+	body := ErrorBody{
+		Code:    code,
+		Message: message,
+	}
+	if rw.Debug {
+		body.Debug = &DebugInfo{Stack: string(debug.Stack())}
+		if err != nil {
+			body.Debug.Error = err.Error()
+		}
+	}
+
+	buf := rw.getBuffer()
+	defer rw.putBuffer(buf)
+	json.NewEncoder(buf).Encode(ErrorEnvelope{Error: body})
+	writeJSON(w, status, buf.Bytes())
+}
+
+func (rw Writer) BadRequest(w http.ResponseWriter, message string, err error) {
+	rw.Error(w, http.StatusBadRequest, "BAD_REQUEST", message, err)
+}
+
+func (rw Writer) InternalError(w http.ResponseWriter, err error) {
+	rw.Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error", err)
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeEnvelope(t *testing.T, rec *httptest.ResponseRecorder) map[string]map[string]interface{} {
+	var envelope map[string]map[string]interface{}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &envelope))
+	return envelope
+}
+
+func TestGivenDevelopmentMode_WhenInternalError_ThenShouldIncludeTheDebugField(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	Writer{Debug: true}.InternalError(rec, errors.New("dial tcp 127.0.0.1:3306: connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	body := decodeEnvelope(t, rec)["error"]
+	assert.Equal(t, "INTERNAL_ERROR", body["code"])
+	assert.Equal(t, "internal server error", body["message"])
+	debug := body["debug"].(map[string]interface{})
+	assert.Equal(t, "dial tcp 127.0.0.1:3306: connection refused", debug["error"])
+	assert.Contains(t, debug["stack"], "response.Writer.InternalError")
+}
+
+func TestGivenProductionMode_WhenInternalError_ThenShouldNotLeakTheUnderlyingError(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	Writer{}.InternalError(rec, errors.New("dial tcp 127.0.0.1:3306: connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	body := decodeEnvelope(t, rec)["error"]
+	assert.Equal(t, "INTERNAL_ERROR", body["code"])
+	assert.NotContains(t, body, "debug")
+	assert.NotContains(t, rec.Body.String(), "3306")
+}
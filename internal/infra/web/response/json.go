@@ -0,0 +1,66 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Appender is implemented by the hot response types, which append their JSON
+// encoding without reflection. AppendJSON must produce the same bytes as
+// json.Marshal.
+type Appender interface {
+	AppendJSON(b []byte) ([]byte, error)
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func (rw Writer) getBuffer() *bytes.Buffer {
+	if rw.MaxPooledBufferSize <= 0 {
+		return new(bytes.Buffer)
+	}
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func (rw Writer) putBuffer(buf *bytes.Buffer) {
+	if rw.MaxPooledBufferSize <= 0 || buf.Cap() > rw.MaxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// JSON writes v as a JSON response with the given status. The body is encoded
+// into a buffer first, so an encoding failure still produces a clean 500
+// instead of a truncated body.
+func (rw Writer) JSON(w http.ResponseWriter, status int, v interface{}) {
+	buf := rw.getBuffer()
+	defer rw.putBuffer(buf)
+
+	if appender, ok := v.(Appender); ok && rw.UseAppenders {
+		body, err := appender.AppendJSON(buf.AvailableBuffer())
+		if err != nil {
+			rw.InternalError(w, err)
+			return
+		}
+		buf.Write(body)
+		buf.WriteByte('\n')
+		writeJSON(w, status, buf.Bytes())
+		return
+	}
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		rw.InternalError(w, err)
+		return
+	}
+	writeJSON(w, status, buf.Bytes())
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,100 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listPayload struct {
+	Orders []map[string]interface{} `json:"orders"`
+}
+
+func newListPayload(n int, tag string) listPayload {
+	payload := listPayload{}
+	for i := 0; i < n; i++ {
+		payload.Orders = append(payload.Orders, map[string]interface{}{"id": fmt.Sprintf("%s-%d", tag, i), "price": 10.5})
+	}
+	return payload
+}
+
+func TestGivenConcurrentResponses_WhenJSON_ThenEachShouldGetItsOwnBody(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tag := fmt.Sprintf("worker%d", i)
+			rec := httptest.NewRecorder()
+
+			Writer{MaxPooledBufferSize: 64 << 10}.JSON(rec, http.StatusOK, newListPayload(20, tag))
+
+			var got listPayload
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, newListPayload(20, tag).Orders[19]["id"], got.Orders[19]["id"])
+			assert.Len(t, got.Orders, 20)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestGivenAValueThatCannotBeEncoded_WhenJSON_ThenShouldRespondInternalError(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	Writer{}.JSON(rec, http.StatusOK, math.Inf(1))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "INTERNAL_ERROR", decodeEnvelope(t, rec)["error"]["code"])
+}
+
+type appenderStub struct {
+	Name string `json:"name"`
+	err  error
+}
+
+func (a appenderStub) AppendJSON(b []byte) ([]byte, error) {
+	return append(b, `{"name":"appended"}`...), a.err
+}
+
+func TestGivenAnAppender_WhenJSON_ThenShouldOnlyUseAppendJSONWhenEnabled(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Writer{}.JSON(rec, http.StatusOK, appenderStub{Name: "marshaled"})
+	assert.Equal(t, "{\"name\":\"marshaled\"}\n", rec.Body.String())
+
+	appenders := Writer{UseAppenders: true}
+	rec = httptest.NewRecorder()
+	appenders.JSON(rec, http.StatusOK, appenderStub{Name: "marshaled"})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"name\":\"appended\"}\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	appenders.JSON(rec, http.StatusOK, appenderStub{err: errors.New("json: unsupported value: +Inf")})
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func benchmarkJSON(b *testing.B, maxPooled int) {
+	responses := Writer{MaxPooledBufferSize: maxPooled}
+	payload := newListPayload(100, "order")
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			responses.JSON(httptest.NewRecorder(), http.StatusOK, payload)
+		}
+	})
+}
+
+func BenchmarkJSONPooled(b *testing.B) {
+	benchmarkJSON(b, 64<<10)
+}
+
+func BenchmarkJSONUnpooled(b *testing.B) {
+	benchmarkJSON(b, 0)
+}
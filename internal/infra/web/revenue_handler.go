@@ -0,0 +1,30 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+)
+
+type WebRevenueHandler struct {
+	// TotalRevenue is shared by every request, so they all use its cache.
+	TotalRevenue *usecase.GetTotalRevenueUseCase
+	// Response writes the JSON bodies and error envelopes.
+	Response response.Writer
+}
+
+func NewWebRevenueHandler(totalRevenue *usecase.GetTotalRevenueUseCase) *WebRevenueHandler {
+	return &WebRevenueHandler{TotalRevenue: totalRevenue}
+}
+
+// Revenue reports the total revenue of the orders that were not cancelled.
+func (h *WebRevenueHandler) Revenue(w http.ResponseWriter, r *http.Request) {
+	output, err := h.TotalRevenue.Execute(r.Context())
+	if err != nil {
+		h.Response.InternalError(w, err)
+		return
+	}
+
+	h.Response.JSON(w, http.StatusOK, output)
+}
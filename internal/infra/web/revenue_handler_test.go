@@ -0,0 +1,40 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+type revenueReaderStub struct {
+	total float64
+	err   error
+}
+
+func (r *revenueReaderStub) TotalRevenue(ctx context.Context) (float64, error) {
+	return r.total, r.err
+}
+
+func TestGivenOrders_WhenRevenue_ThenShouldReportTheTotal(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handler := NewWebRevenueHandler(usecase.NewGetTotalRevenueUseCase(&revenueReaderStub{total: 120.5}))
+
+	handler.Revenue(rec, httptest.NewRequest(http.MethodGet, "/orders/revenue", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total_revenue":120.5`)
+}
+
+func TestGivenAFailingReader_WhenRevenue_ThenShouldRespondInternalError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handler := NewWebRevenueHandler(usecase.NewGetTotalRevenueUseCase(&revenueReaderStub{err: errors.New("connection refused")}))
+
+	handler.Revenue(rec, httptest.NewRequest(http.MethodGet, "/orders/revenue", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,189 @@
+package webserver
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RequireContentType rejects requests whose Content-Type is not one of the
+// allowed media types with 415 Unsupported Media Type. Parameters such as
+// charset are ignored when matching. Requests without a body, such as action
+// routes, carry nothing to negotiate and pass through. The 415 is written
+// through responses.
+func RequireContentType(responses response.Writer, allowed ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err == nil {
+				for _, t := range allowed {
+					if strings.EqualFold(mediaType, t) {
+						next.ServeHTTP(w, r)
+						return
+					}
+				}
+			}
+			responses.Error(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "unsupported media type", err)
+		})
+	}
+}
+
+// PlaygroundCSP is the Content-Security-Policy the GraphQL playground can run
+// under: its page is an inline script loading GraphiQL from jsDelivr, and it
+// queries the same origin over HTTP and WebSocket.
+const PlaygroundCSP = "default-src 'none'; script-src 'unsafe-inline' https://cdn.jsdelivr.net; " +
+	"style-src 'unsafe-inline' https://cdn.jsdelivr.net; img-src data: https://cdn.jsdelivr.net; " +
+	"font-src https://cdn.jsdelivr.net; connect-src 'self'; frame-ancestors 'none'"
+
+// RelaxContentSecurityPolicy replaces the Content-Security-Policy set by
+// SecurityHeaders with csp, for pages the default policy would break. Without
+// SecurityHeaders no header is set, so none is added.
+func RelaxContentSecurityPolicy(csp string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if w.Header().Get("Content-Security-Policy") != "" {
+				w.Header().Set("Content-Security-Policy", csp)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Deprecated marks every response with the Deprecation header and the RFC 8594
+// Sunset header announcing when the route will be removed.
+func Deprecated(sunset time.Time) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SecurityHeaders sets the standard hardening headers on every response:
+// nosniff, no framing, no referrer, and csp as Content-Security-Policy unless
+// it is empty.
+func SecurityHeaders(csp string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("X-Frame-Options", "DENY")
+			w.Header().Set("Referrer-Policy", "no-referrer")
+			if csp != "" {
+				w.Header().Set("Content-Security-Policy", csp)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CountInFlight keeps gauge at the number of requests being served. The
+// decrement is deferred, so a panicking handler is still counted out.
+func CountInFlight(gauge prometheus.Gauge) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gauge.Inc()
+			defer gauge.Dec()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ObserveDuration records how long each request took in observer, labelled
+// with its method, the route pattern it matched and its status. Requests that
+// match no route share the "unmatched" route, so unknown paths cannot grow the
+// number of series.
+func ObserveDuration(observer prometheus.ObserverVec) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			observer.WithLabelValues(r.Method, route, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// ParseDeprecatedRoutes parses a comma separated list of "METHOD /path=YYYY-MM-DD"
+// entries into sunset dates keyed by "METHOD /path".
+func ParseDeprecatedRoutes(value string) (map[string]time.Time, error) {
+	routes := make(map[string]time.Time)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, date, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid deprecated route %q: expected METHOD /path=YYYY-MM-DD", entry)
+		}
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid deprecated route %q: expected METHOD /path=YYYY-MM-DD", entry)
+		}
+		sunset, err := time.Parse(time.DateOnly, strings.TrimSpace(date))
+		if err != nil {
+			return nil, fmt.Errorf("invalid sunset date for %q: %w", route, err)
+		}
+		routes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = sunset
+	}
+	return routes, nil
+}
+
+type TrailingSlashMode string
+
+const (
+	// TrailingSlashStrip routes "/order/" as "/order".
+	TrailingSlashStrip TrailingSlashMode = "strip"
+	// TrailingSlashRedirect answers "/order/" with a redirect to "/order".
+	TrailingSlashRedirect TrailingSlashMode = "redirect"
+)
+
+// ParseTrailingSlashMode validates mode as read from TRAILING_SLASH. An empty
+// mode keeps the router's exact matching.
+func ParseTrailingSlashMode(mode string) (TrailingSlashMode, error) {
+	switch m := TrailingSlashMode(mode); m {
+	case "", TrailingSlashStrip, TrailingSlashRedirect:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown trailing slash mode %q", mode)
+}
+
+// trailingSlash applies the server's TrailingSlash mode with chi's
+// StripSlashes or RedirectSlashes middleware.
+func (s *WebServer) trailingSlash(next http.Handler) http.Handler {
+	strip := middleware.StripSlashes(next)
+	redirect := middleware.RedirectSlashes(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch s.TrailingSlash {
+		case TrailingSlashStrip:
+			strip.ServeHTTP(w, r)
+		case TrailingSlashRedirect:
+			redirect.ServeHTTP(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
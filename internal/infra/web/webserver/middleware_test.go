@@ -0,0 +1,188 @@
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContentTypeTestServer() *WebServer {
+	server := NewWebServer(":0")
+	server.AddHandler(http.MethodPost, "/order", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	return server
+}
+
+func TestGivenATextPlainBody_WhenPostingToAWriteRoute_ThenShouldReturnUnsupportedMediaType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader("id=1"))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+
+	newContentTypeTestServer().Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), `"code":"UNSUPPORTED_MEDIA_TYPE"`)
+}
+
+func TestGivenAJSONBody_WhenPostingToAWriteRoute_ThenShouldReachTheHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"1"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+
+	newContentTypeTestServer().Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestGivenAMissingContentType_WhenPostingToAWriteRoute_ThenShouldReturnUnsupportedMediaType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/order", strings.NewReader(`{"id":"1"}`))
+	rec := httptest.NewRecorder()
+
+	newContentTypeTestServer().Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestGivenNoBodyAndNoContentType_WhenPostingToAnActionRoute_ThenShouldReachTheHandler(t *testing.T) {
+	server := NewWebServer(":0")
+	server.AddHandler(http.MethodPost, "/orders/{id}/duplicate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/orders/1/duplicate", nil)
+	rec := httptest.NewRecorder()
+
+	server.Router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestGivenADeprecatedRoute_WhenRequested_ThenShouldEmitDeprecationHeaders(t *testing.T) {
+	routes, err := ParseDeprecatedRoutes("GET /order=2026-12-31")
+	assert.Nil(t, err)
+
+	server := NewWebServer(":0")
+	server.DeprecatedRoutes = routes
+	server.AddHandler(http.MethodGet, "/order", func(w http.ResponseWriter, r *http.Request) {})
+	server.AddHandler(http.MethodGet, "/orders/by-status", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 31 Dec 2026 00:00:00 GMT", rec.Header().Get("Sunset"))
+
+	rec = httptest.NewRecorder()
+	server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/by-status", nil))
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+}
+
+func TestGivenAMalformedDeprecatedRoute_WhenParse_ThenShouldReturnAnError(t *testing.T) {
+	_, err := ParseDeprecatedRoutes("GET /order")
+	assert.Error(t, err)
+
+	_, err = ParseDeprecatedRoutes("GET /order=31/12/2026")
+	assert.Error(t, err)
+}
+
+func TestGivenSecurityHeadersEnabled_WhenRequestingAnyRoute_ThenShouldSetThem(t *testing.T) {
+	server := NewWebServer(":0")
+	server.SecurityHeaders = true
+	server.ContentSecurityPolicy = "default-src 'none'"
+	server.AddHandler(http.MethodGet, "/order", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, path := range []string{"/order", "/missing"} {
+		rec := httptest.NewRecorder()
+		server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"), path)
+		assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"), path)
+		assert.Equal(t, "no-referrer", rec.Header().Get("Referrer-Policy"), path)
+		assert.Equal(t, "default-src 'none'", rec.Header().Get("Content-Security-Policy"), path)
+	}
+}
+
+func TestGivenSecurityHeadersDisabled_WhenRequestingARoute_ThenShouldNotSetThem(t *testing.T) {
+	server := NewWebServer(":0")
+	server.AddHandler(http.MethodGet, "/order", func(w http.ResponseWriter, r *http.Request) {})
+	rec := httptest.NewRecorder()
+
+	server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+	assert.Empty(t, rec.Header().Get("X-Content-Type-Options"))
+}
+
+func TestGivenConcurrentSlowRequests_WhenCountInFlight_ThenTheGaugeShouldTrackThem(t *testing.T) {
+	const requests = 5
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_in_flight"})
+	started := make(chan struct{}, requests)
+	release := make(chan struct{})
+	handler := CountInFlight(gauge)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/order", nil))
+		}()
+	}
+	for i := 0; i < requests; i++ {
+		<-started
+	}
+	assert.Equal(t, float64(requests), testutil.ToFloat64(gauge))
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 0.0, testutil.ToFloat64(gauge))
+}
+
+func TestGivenAPanickingHandler_WhenCountInFlight_ThenShouldStillDecrement(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_in_flight"})
+	handler := CountInFlight(gauge)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/order", nil))
+	})
+	assert.Equal(t, 0.0, testutil.ToFloat64(gauge))
+}
+
+func TestGivenARequestDurationHistogram_WhenServingRequests_ThenShouldObserveThemByRoutePattern(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration_seconds"}, []string{"method", "route", "status"})
+	registry.MustRegister(histogram)
+	server := NewWebServer(":0")
+	server.RequestDuration = histogram
+	server.AddHandler(http.MethodGet, "/order/{id}/history", func(w http.ResponseWriter, r *http.Request) {})
+
+	server.Router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/order/1/history", nil))
+	server.Router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/order/2/history", nil))
+	server.Router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	families, err := registry.Gather()
+	assert.Nil(t, err)
+	observed := make(map[string]uint64)
+	for _, metric := range families[0].GetMetric() {
+		var labels []string
+		for _, label := range metric.GetLabel() {
+			labels = append(labels, label.GetValue())
+		}
+		observed[strings.Join(labels, " ")] = metric.GetHistogram().GetSampleCount()
+	}
+	assert.Equal(t, map[string]uint64{
+		"GET /order/{id}/history 200": 2,
+		"GET unmatched 404":           1,
+	}, observed)
+}
@@ -0,0 +1,198 @@
+package webserver
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+)
+
+// RateLimit allows Requests per Window to each client. The zero value does not
+// limit.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseRateLimit parses "N/duration", e.g. "100/1m". An empty value is no
+// limit.
+func ParseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return RateLimit{}, nil
+	}
+	requests, window, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected N/duration", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected a positive number of requests", value)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || d <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected a positive window", value)
+	}
+	return RateLimit{Requests: n, Window: d}, nil
+}
+
+// ParseRouteRateLimits parses a comma separated list of "METHOD /path=N/duration"
+// entries into limits keyed by "METHOD /path".
+func ParseRouteRateLimits(value string) (map[string]RateLimit, error) {
+	routes := make(map[string]RateLimit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route rate limit %q: expected METHOD /path=N/duration", entry)
+		}
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid route rate limit %q: expected METHOD /path=N/duration", entry)
+		}
+		parsed, err := ParseRateLimit(limit)
+		if err != nil {
+			return nil, err
+		}
+		routes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = parsed
+	}
+	return routes, nil
+}
+
+// ParseTrustedProxies parses a comma separated list of proxy addresses, as
+// CIDR prefixes or single IPs, e.g. "10.0.0.0/8, 192.0.2.10".
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: expected an IP or CIDR prefix", entry)
+			}
+			proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: expected an IP or CIDR prefix", entry)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// clientIP returns the address a request is rate limited by. Requests from a
+// trusted proxy are attributed to the last X-Forwarded-For hop that is not a
+// trusted proxy itself, or else to X-Real-IP; from anyone else the headers
+// could be forged, so the connection's address is used.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+	if !isTrusted(client, trusted) {
+		return client
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !isTrusted(hop, trusted) {
+			return hop
+		}
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		return realIP
+	}
+	return client
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter counts requests per client IP in fixed windows.
+type rateLimiter struct {
+	limit     RateLimit
+	trusted   []netip.Prefix
+	now       func() time.Time
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	nextSweep time.Time
+	response  response.Writer
+}
+
+func newRateLimiter(limit RateLimit, trusted []netip.Prefix) *rateLimiter {
+	return &rateLimiter{limit: limit, trusted: trusted, now: time.Now, windows: make(map[string]*rateWindow)}
+}
+
+// allow records a request from client and reports whether it is within the
+// limit, and otherwise how long until the client's window resets.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.After(l.nextSweep) {
+		for key, w := range l.windows {
+			if now.Sub(w.start) >= l.limit.Window {
+				delete(l.windows, key)
+			}
+		}
+		l.nextSweep = now.Add(l.limit.Window)
+	}
+
+	w, ok := l.windows[client]
+	if !ok || now.Sub(w.start) >= l.limit.Window {
+		w = &rateWindow{start: now}
+		l.windows[client] = w
+	}
+	if w.count >= l.limit.Requests {
+		return false, w.start.Add(l.limit.Window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// middleware answers requests over the limit with 429 Too Many Requests and a
+// Retry-After header.
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := l.allow(clientIP(r, l.trusted)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+			l.response.Error(w, http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "rate limit exceeded", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
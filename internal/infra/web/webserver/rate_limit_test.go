@@ -0,0 +1,96 @@
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenARouteWithAStricterLimit_WhenRequestedRepeatedly_ThenShouldThrottleItSooner(t *testing.T) {
+	server := NewWebServer(":0")
+	server.RateLimit = RateLimit{Requests: 5, Window: time.Minute}
+	server.RouteRateLimits = map[string]RateLimit{"GET /export": {Requests: 2, Window: time.Minute}}
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	server.AddHandler(http.MethodGet, "/export", handler)
+	server.AddHandler(http.MethodGet, "/order", handler)
+
+	statuses := func(path string) []int {
+		var codes []int
+		for i := 0; i < 3; i++ {
+			rec := httptest.NewRecorder()
+			server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			codes = append(codes, rec.Code)
+		}
+		return codes
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, statuses("/export"))
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusOK}, statuses("/order"))
+}
+
+func TestGivenAnExhaustedWindow_WhenItElapses_ThenShouldAllowTheClientAgain(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(RateLimit{Requests: 1, Window: time.Minute}, nil)
+	limiter.now = func() time.Time { return now }
+
+	ok, _ := limiter.allow("10.0.0.1")
+	assert.True(t, ok)
+	ok, retryAfter := limiter.allow("10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, retryAfter)
+	ok, _ = limiter.allow("10.0.0.2")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	ok, _ = limiter.allow("10.0.0.1")
+	assert.True(t, ok)
+}
+
+func TestGivenRouteRateLimits_WhenParseRouteRateLimits_ThenShouldKeyThemByRoute(t *testing.T) {
+	limits, err := ParseRouteRateLimits("get /orders/export=10/1m, POST /order=100/1s")
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]RateLimit{
+		"GET /orders/export": {Requests: 10, Window: time.Minute},
+		"POST /order":        {Requests: 100, Window: time.Second},
+	}, limits)
+
+	_, err = ParseRouteRateLimits("GET /order=fast")
+	assert.EqualError(t, err, `invalid rate limit "fast": expected N/duration`)
+}
+
+func TestGivenTrustedProxies_WhenRateLimiting_ThenShouldCountTheForwardedClient(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.0.2.10")
+	assert.Nil(t, err)
+	server := NewWebServer(":0")
+	server.RateLimit = RateLimit{Requests: 1, Window: time.Minute}
+	server.TrustedProxies = trusted
+	server.AddHandler(http.MethodGet, "/order", func(w http.ResponseWriter, r *http.Request) {})
+
+	request := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/order", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		server.Router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "198.51.100.2, 10.0.0.2"))
+	assert.Equal(t, http.StatusTooManyRequests, request("192.0.2.10:1234", "198.51.100.1"))
+	// An untrusted client cannot dodge its limit by forging the header.
+	assert.Equal(t, http.StatusOK, request("203.0.113.5:1234", "198.51.100.3"))
+	assert.Equal(t, http.StatusTooManyRequests, request("203.0.113.5:1234", "198.51.100.4"))
+}
+
+func TestGivenAMalformedTrustedProxy_WhenParseTrustedProxies_ThenShouldReturnAnError(t *testing.T) {
+	_, err := ParseTrustedProxies("10.0.0.0/8, proxy.internal")
+
+	assert.EqualError(t, err, `invalid trusted proxy "proxy.internal": expected an IP or CIDR prefix`)
+}
@@ -0,0 +1,113 @@
+package webserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// RequestIDGenerator returns a new request ID.
+type RequestIDGenerator func() string
+
+// NewRequestIDGenerator returns the generator for format: "uuid" (random
+// UUIDv4), "ulid" (lexicographically sortable by creation time) or "hex" (32
+// hex characters, compatible with W3C trace IDs). An empty format returns nil,
+// which keeps chi's default host/random-counter IDs.
+func NewRequestIDGenerator(format string) (RequestIDGenerator, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "uuid":
+		return uuid.NewString, nil
+	case "ulid":
+		return newULID, nil
+	case "hex":
+		return newHexID, nil
+	}
+	return nil, fmt.Errorf("unknown request id format %q", format)
+}
+
+// MaxRequestIDLength bounds incoming request IDs. They are stored as the
+// trace_id of created orders, a varchar(255) column.
+const MaxRequestIDLength = 255
+
+// validRequestID reports whether an incoming request ID can be kept: at most
+// MaxRequestIDLength printable ASCII characters, without spaces.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestID behaves like chi's RequestID middleware, keeping an incoming ID
+// from the RequestIDHeader header, but generates new IDs with
+// GenerateRequestID when set. An incoming ID that is too long or has
+// characters other than printable ASCII is replaced by a generated one.
+func (s *WebServer) requestID(next http.Handler) http.Handler {
+	chiRequestID := middleware.RequestID(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := s.RequestIDHeader
+		if header == "" {
+			header = middleware.RequestIDHeader
+		}
+		id := r.Header.Get(header)
+		if !validRequestID(id) {
+			id = ""
+		}
+		if id == "" && s.GenerateRequestID == nil {
+			// chi trusts its own header as is, so it must not see one that
+			// was rejected or that another header replaces.
+			if r.Header.Get(middleware.RequestIDHeader) != "" {
+				r = r.Clone(r.Context())
+				r.Header.Del(middleware.RequestIDHeader)
+			}
+			chiRequestID.ServeHTTP(w, r)
+			return
+		}
+		if id == "" {
+			id = s.GenerateRequestID()
+		}
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID encodes a 48-bit millisecond timestamp followed by 80 random bits in
+// Crockford base32, as specified by https://github.com/ulid/spec.
+func newULID() string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(time.Now().UnixMilli())<<16)
+	rand.Read(id[6:])
+
+	// 128 bits are 26 base32 characters, the first one carrying 3 bits.
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+func newHexID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
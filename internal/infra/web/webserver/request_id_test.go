@@ -0,0 +1,99 @@
+package webserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func requestIDFor(t *testing.T, format string, header string) string {
+	generate, err := NewRequestIDGenerator(format)
+	assert.Nil(t, err)
+	server := NewWebServer(":0")
+	server.GenerateRequestID = generate
+	var id string
+	server.AddHandler(http.MethodGet, "/order", func(w http.ResponseWriter, r *http.Request) {
+		id = middleware.GetReqID(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	if header != "" {
+		req.Header.Set(middleware.RequestIDHeader, header)
+	}
+	server.Router.ServeHTTP(httptest.NewRecorder(), req)
+	return id
+}
+
+func TestGivenARequestIDFormat_WhenServingARequest_ThenShouldGenerateIDsInThatFormat(t *testing.T) {
+	formats := map[string]*regexp.Regexp{
+		"uuid": regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		"ulid": regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`),
+		"hex":  regexp.MustCompile(`^[0-9a-f]{32}$`),
+		"":     regexp.MustCompile(`^.+/.+-\d{6}$`),
+	}
+	for format, pattern := range formats {
+		assert.Regexp(t, pattern, requestIDFor(t, format, ""), format)
+	}
+}
+
+func TestGivenAnIncomingRequestID_WhenServingARequest_ThenShouldKeepIt(t *testing.T) {
+	assert.Equal(t, "caller-id", requestIDFor(t, "hex", "caller-id"))
+}
+
+func TestGivenACustomRequestIDHeader_WhenServingARequest_ThenShouldReadTheIDFromIt(t *testing.T) {
+	server := NewWebServer(":0")
+	server.RequestIDHeader = "X-Correlation-Id"
+	var id string
+	server.AddHandler(http.MethodGet, "/order", func(w http.ResponseWriter, r *http.Request) {
+		id = middleware.GetReqID(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set("X-Correlation-Id", "caller-id")
+	server.Router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "caller-id", id)
+
+	req = httptest.NewRequest(http.MethodGet, "/order", nil)
+	req.Header.Set(middleware.RequestIDHeader, "ignored-id")
+	server.Router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Regexp(t, `^.+/.+-\d{6}$`, id)
+}
+
+func TestGivenAnInvalidIncomingRequestID_WhenServingARequest_ThenShouldReplaceIt(t *testing.T) {
+	for name, header := range map[string]string{
+		"too long":         strings.Repeat("x", MaxRequestIDLength+1),
+		"control":          "caller\x01id",
+		"non-ASCII":        "caller-ïd",
+		"longest accepted": strings.Repeat("x", MaxRequestIDLength),
+	} {
+		for _, format := range []string{"", "hex"} {
+			id := requestIDFor(t, format, header)
+			if name == "longest accepted" {
+				assert.Equal(t, header, id, name)
+				continue
+			}
+			assert.NotEqual(t, header, id, name)
+			assert.NotEmpty(t, id, name)
+		}
+	}
+}
+
+func TestGivenULIDsCreatedOverTime_WhenComparingThem_ThenShouldSortChronologically(t *testing.T) {
+	first := newULID()
+	time.Sleep(2 * time.Millisecond)
+	second := newULID()
+
+	assert.Less(t, first, second)
+}
+
+func TestGivenAnUnknownFormat_WhenNewRequestIDGenerator_ThenShouldReturnAnError(t *testing.T) {
+	_, err := NewRequestIDGenerator("snowflake")
+
+	assert.EqualError(t, err, `unknown request id format "snowflake"`)
+}
@@ -1,31 +1,178 @@
 package webserver
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"net/netip"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/metrics"
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// routeMethods are the methods checked when building the Allow header.
+var routeMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+}
+
 type WebServer struct {
 	Router        chi.Router
 	WebServerPort string
+	// DeprecatedRoutes maps "METHOD /path" to the route's sunset date.
+	DeprecatedRoutes map[string]time.Time
+	// GenerateRequestID creates the ID of requests that arrive without a
+	// request ID header. When nil, chi's default format is used.
+	GenerateRequestID RequestIDGenerator
+	// RequestIDHeader names the header an incoming request ID is read from.
+	// Empty means X-Request-Id.
+	RequestIDHeader string
+	// TrailingSlash decides how "/order/" is served when only "/order" is
+	// registered. The zero value leaves such requests unmatched (404).
+	TrailingSlash TrailingSlashMode
+	// RateLimit is applied to every route added afterwards, each route keeping
+	// its own count, unless RouteRateLimits has an entry for it. RouteRateLimits
+	// is keyed by "METHOD /path".
+	RateLimit       RateLimit
+	RouteRateLimits map[string]RateLimit
+	// TrustedProxies are the addresses whose X-Forwarded-For and X-Real-IP
+	// headers the rate limit believes. Like RateLimit it applies to routes
+	// added afterwards.
+	TrustedProxies []netip.Prefix
+	// SecurityHeaders adds the SecurityHeaders middleware to every response,
+	// with ContentSecurityPolicy as its policy.
+	SecurityHeaders       bool
+	ContentSecurityPolicy string
+	// RequestDuration, when set, records the duration of every request
+	// through ObserveDuration.
+	RequestDuration prometheus.ObserverVec
+	// Response writes the error envelopes of the 404, 405, 415 and 429
+	// responses the server answers itself. Like RateLimit, the 415 and 429
+	// use the value set when the route is added.
+	Response response.Writer
+	server   *http.Server
 }
 
 func NewWebServer(serverPort string) *WebServer {
 	router := chi.NewRouter()
-	router.Use(middleware.Logger)
-	return &WebServer{
+	s := &WebServer{
 		Router:        router,
 		WebServerPort: serverPort,
+		server:        &http.Server{Addr: serverPort, Handler: router},
 	}
+	router.Use(CountInFlight(metrics.HTTPInFlightRequests))
+	router.Use(s.observeDuration)
+	router.Use(s.requestID)
+	router.Use(s.securityHeaders)
+	router.Use(middleware.Logger)
+	router.Use(s.trailingSlash)
+	router.NotFound(s.notFound)
+	router.MethodNotAllowed(s.methodNotAllowed)
+	return s
 }
 
+// AddHandler registers handler for method and path. Write routes (POST, PUT
+// and PATCH) only accept application/json bodies, routes listed in
+// DeprecatedRoutes announce their sunset date, and requests over the route's
+// rate limit are rejected.
 func (s *WebServer) AddHandler(method, path string, handler http.HandlerFunc) {
-	s.Router.Method(method, path, handler)
+	var h http.Handler = handler
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		h = RequireContentType(s.Response, "application/json")(h)
+	}
+	if sunset, ok := s.DeprecatedRoutes[method+" "+path]; ok {
+		h = Deprecated(sunset)(h)
+	}
+	limit, ok := s.RouteRateLimits[method+" "+path]
+	if !ok {
+		limit = s.RateLimit
+	}
+	if limit.Requests > 0 {
+		limiter := newRateLimiter(limit, s.TrustedProxies)
+		limiter.response = s.Response
+		h = limiter.middleware(h)
+	}
+	s.Router.Method(method, path, h)
+}
+
+// Handle registers handler for every method on path. Unlike AddHandler it does
+// not enforce a JSON body, so handlers with their own content negotiation, such
+// as GraphQL, can be mounted.
+func (s *WebServer) Handle(path string, handler http.Handler) {
+	s.Router.Handle(path, handler)
+}
+
+// securityHeaders applies SecurityHeaders when enabled. Like trailingSlash it
+// reads the settings per request, as they are set after NewWebServer.
+func (s *WebServer) securityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.SecurityHeaders {
+			next.ServeHTTP(w, r)
+			return
+		}
+		SecurityHeaders(s.ContentSecurityPolicy)(next).ServeHTTP(w, r)
+	})
+}
+
+// observeDuration applies ObserveDuration when RequestDuration is set, which
+// happens after NewWebServer.
+func (s *WebServer) observeDuration(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.RequestDuration == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ObserveDuration(s.RequestDuration)(next).ServeHTTP(w, r)
+	})
+}
+
+// notFound answers unknown routes with the JSON error envelope.
+func (s *WebServer) notFound(w http.ResponseWriter, r *http.Request) {
+	s.Response.Error(w, http.StatusNotFound, "NOT_FOUND", "route not found", nil)
+}
+
+// methodNotAllowed answers with the JSON error envelope and an Allow header
+// listing the methods registered for the requested path.
+func (s *WebServer) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	var allowed []string
+	for _, method := range routeMethods {
+		if s.Router.Match(chi.NewRouteContext(), method, r.URL.Path) {
+			allowed = append(allowed, method)
+		}
+	}
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	s.Response.Error(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed", nil)
+}
+
+// Listen binds WebServerPort, so a port that cannot be bound fails startup
+// instead of the background Serve.
+func (s *WebServer) Listen() (net.Listener, error) {
+	return net.Listen("tcp", s.server.Addr)
+}
+
+// Serve serves lis until Stop, after which it returns nil. Any other error
+// means the server stopped serving on its own.
+func (s *WebServer) Serve(lis net.Listener) error {
+	if err := s.server.Serve(lis); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
-// start the server
-func (s *WebServer) Start() {
-	http.ListenAndServe(s.WebServerPort, s.Router)
+// Stop stops accepting connections and waits for in-flight requests to finish
+// or ctx to be done.
+func (s *WebServer) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
 }
@@ -0,0 +1,99 @@
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/infra/web/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderTestServer() *WebServer {
+	server := NewWebServer(":0")
+	handler := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(r.Method + " order")) }
+	server.AddHandler(http.MethodPost, "/order", handler)
+	server.AddHandler(http.MethodGet, "/order", handler)
+	return server
+}
+
+func TestGivenAnUnsupportedMethod_WhenRequestingARoute_ThenShouldReturnAJSONMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	newOrderTestServer().Router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/order", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, POST", rec.Header().Get("Allow"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var envelope response.ErrorEnvelope
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&envelope))
+	assert.Equal(t, "METHOD_NOT_ALLOWED", envelope.Error.Code)
+}
+
+func TestGivenAnUnknownPath_WhenRequestingIt_ThenShouldReturnAJSONNotFound(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	newOrderTestServer().Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var envelope response.ErrorEnvelope
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&envelope))
+	assert.Equal(t, "NOT_FOUND", envelope.Error.Code)
+	assert.Equal(t, "route not found", envelope.Error.Message)
+}
+
+func TestGivenStripMode_WhenRequestingAPathWithATrailingSlash_ThenShouldServeTheRoute(t *testing.T) {
+	server := newOrderTestServer()
+	server.TrailingSlash = TrailingSlashStrip
+	with, without := httptest.NewRecorder(), httptest.NewRecorder()
+
+	server.Router.ServeHTTP(with, httptest.NewRequest(http.MethodGet, "/order/", nil))
+	server.Router.ServeHTTP(without, httptest.NewRequest(http.MethodGet, "/order", nil))
+
+	assert.Equal(t, http.StatusOK, with.Code)
+	assert.Equal(t, without.Code, with.Code)
+	assert.Equal(t, without.Body.String(), with.Body.String())
+}
+
+func TestGivenRedirectMode_WhenRequestingAPathWithATrailingSlash_ThenShouldRedirect(t *testing.T) {
+	server := newOrderTestServer()
+	server.TrailingSlash = TrailingSlashRedirect
+	rec := httptest.NewRecorder()
+
+	server.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order/?limit=5", nil))
+
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/order?limit=5", rec.Header().Get("Location"))
+}
+
+func TestGivenAnUnknownMode_WhenParseTrailingSlashMode_ThenShouldReturnAnError(t *testing.T) {
+	_, err := ParseTrailingSlashMode("append")
+
+	assert.EqualError(t, err, `unknown trailing slash mode "append"`)
+}
+
+func TestGivenAPortInUse_WhenListen_ThenShouldFail(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer taken.Close()
+
+	_, err = NewWebServer(taken.Addr().String()).Listen()
+
+	assert.Error(t, err)
+}
+
+func TestGivenAServingServer_WhenStop_ThenServeShouldReturnNil(t *testing.T) {
+	server := NewWebServer("127.0.0.1:0")
+	lis, err := server.Listen()
+	assert.Nil(t, err)
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(lis) }()
+
+	assert.Nil(t, server.Stop(context.Background()))
+
+	assert.Nil(t, <-served)
+}
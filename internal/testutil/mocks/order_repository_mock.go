@@ -0,0 +1,104 @@
+// Package mocks provides testify doubles for the entity interfaces, shared by
+// the use case and transport tests.
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/stretchr/testify/mock"
+)
+
+type OrderRepositoryMock struct {
+	mock.Mock
+}
+
+var _ entity.OrderRepositoryInterface = (*OrderRepositoryMock)(nil)
+
+func (m *OrderRepositoryMock) Save(ctx context.Context, order *entity.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *OrderRepositoryMock) SaveOrUpdate(ctx context.Context, order *entity.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *OrderRepositoryMock) SaveBatch(ctx context.Context, orders []*entity.Order) error {
+	args := m.Called(ctx, orders)
+	return args.Error(0)
+}
+
+func (m *OrderRepositoryMock) FindByID(ctx context.Context, id string) (*entity.Order, error) {
+	args := m.Called(ctx, id)
+	order, _ := args.Get(0).(*entity.Order)
+	return order, args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindAll(ctx context.Context) ([]entity.Order, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindPage(ctx context.Context, limit, offset int) ([]entity.Order, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindPageWithTotal(ctx context.Context, limit, offset int) ([]entity.Order, int, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]entity.Order), args.Int(1), args.Error(2)
+}
+
+func (m *OrderRepositoryMock) FindPageAsOf(ctx context.Context, asOf time.Time, limit, offset int) ([]entity.Order, error) {
+	args := m.Called(ctx, asOf, limit, offset)
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindByStatus(ctx context.Context, status string, limit int) ([]entity.Order, error) {
+	args := m.Called(ctx, status, limit)
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) CountByStatus(ctx context.Context) (map[string]int, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) Version(ctx context.Context) (entity.OrderSetVersion, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(entity.OrderSetVersion), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindRecent(ctx context.Context, n int) ([]entity.Order, error) {
+	args := m.Called(ctx, n)
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindMostExpensive(ctx context.Context) (*entity.Order, error) {
+	args := m.Called(ctx)
+	order, _ := args.Get(0).(*entity.Order)
+	return order, args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindUpdatedSince(ctx context.Context, after entity.OrderChangeCursor, limit int) ([]entity.Order, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) SearchByIDPrefix(ctx context.Context, prefix string, limit int) ([]entity.Order, error) {
+	args := m.Called(ctx, prefix, limit)
+	return args.Get(0).([]entity.Order), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) UpdateStatusWhere(ctx context.Context, filter entity.OrderStatusFilter, status string, updatedAt time.Time) (int, error) {
+	args := m.Called(ctx, filter, status, updatedAt)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *OrderRepositoryMock) FindHistory(ctx context.Context, id string) ([]entity.OrderChange, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]entity.OrderChange), args.Error(1)
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+)
+
+// DefaultArchiveMaxAge and DefaultArchiveBatchSize are the MaxAge and
+// BatchSize of new ArchiveOrdersUseCases.
+const (
+	DefaultArchiveMaxAge    = 90 * 24 * time.Hour
+	DefaultArchiveBatchSize = 500
+)
+
+type ArchiveOrdersOutputDTO struct {
+	CreatedBefore time.Time `json:"created_before"`
+	Archived      int       `json:"archived"`
+	Batches       int       `json:"batches"`
+}
+
+type ArchiveOrdersUseCase struct {
+	OrderArchiver   entity.OrderArchiver
+	OrdersArchived  events.EventInterface
+	EventDispatcher events.EventDispatcherInterface
+	// MaxAge is how old, by created_at, an order must be to be archived.
+	MaxAge time.Duration
+	// BatchSize bounds the orders moved per transaction.
+	BatchSize int
+}
+
+func NewArchiveOrdersUseCase(
+	OrderArchiver entity.OrderArchiver,
+	OrdersArchived events.EventInterface,
+	EventDispatcher events.EventDispatcherInterface,
+) *ArchiveOrdersUseCase {
+	if EventDispatcher == nil {
+		EventDispatcher = events.NewNoopDispatcher()
+	}
+	return &ArchiveOrdersUseCase{
+		OrderArchiver:   OrderArchiver,
+		OrdersArchived:  OrdersArchived,
+		EventDispatcher: EventDispatcher,
+		MaxAge:          DefaultArchiveMaxAge,
+		BatchSize:       DefaultArchiveBatchSize,
+	}
+}
+
+// Execute archives every order created more than MaxAge ago, one transaction
+// per batch, until a batch comes back short. The cutoff is fixed when it
+// starts, so orders aging past it meanwhile wait for the next run. On failure
+// the batches already committed stay archived and are counted in the output.
+// One OrdersArchived event is dispatched when at least one order was archived.
+func (a *ArchiveOrdersUseCase) Execute(ctx context.Context) (ArchiveOrdersOutputDTO, error) {
+	if a.BatchSize <= 0 {
+		return ArchiveOrdersOutputDTO{}, errors.New("archive batch size must be positive")
+	}
+	if a.MaxAge <= 0 {
+		return ArchiveOrdersOutputDTO{}, errors.New("archive max age must be positive")
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	output := ArchiveOrdersOutputDTO{CreatedBefore: now.Add(-a.MaxAge)}
+	err := a.archive(ctx, &output, now)
+	if output.Archived > 0 {
+		archived := events.NewFrom(a.OrdersArchived)
+		archived.SetPayload(output)
+		a.EventDispatcher.Dispatch(archived)
+	}
+	return output, err
+}
+
+func (a *ArchiveOrdersUseCase) archive(ctx context.Context, output *ArchiveOrdersOutputDTO, now time.Time) error {
+	for {
+		archived, err := a.OrderArchiver.ArchiveBefore(ctx, output.CreatedBefore, a.BatchSize, now)
+		if err != nil {
+			return err
+		}
+		if archived > 0 {
+			output.Archived += archived
+			output.Batches++
+		}
+		if archived < a.BatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/stretchr/testify/assert"
+)
+
+// archiverStub moves up to limit of its remaining orders per call.
+type archiverStub struct {
+	remaining int
+	cutoffs   []time.Time
+	err       error
+}
+
+func (a *archiverStub) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int, archivedAt time.Time) (int, error) {
+	a.cutoffs = append(a.cutoffs, cutoff)
+	if a.err != nil {
+		return 0, a.err
+	}
+	moved := min(limit, a.remaining)
+	a.remaining -= moved
+	return moved, nil
+}
+
+func TestGivenOldOrders_WhenArchiveOrders_ThenShouldMoveThemInBatchesUntilNoneAreLeft(t *testing.T) {
+	archiver := &archiverStub{remaining: 5}
+	archiveOrders := NewArchiveOrdersUseCase(archiver, event.NewOrdersArchived(), nil)
+	archiveOrders.MaxAge = 24 * time.Hour
+	archiveOrders.BatchSize = 2
+
+	output, err := archiveOrders.Execute(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 5, output.Archived)
+	assert.Equal(t, 3, output.Batches)
+	assert.Equal(t, 0, archiver.remaining)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), output.CreatedBefore, time.Minute)
+	for _, cutoff := range archiver.cutoffs {
+		assert.Equal(t, output.CreatedBefore, cutoff)
+	}
+}
+
+func TestGivenAnExactMultipleOfTheBatchSize_WhenArchiveOrders_ThenShouldStopAfterAnEmptyBatch(t *testing.T) {
+	archiver := &archiverStub{remaining: 4}
+	archiveOrders := NewArchiveOrdersUseCase(archiver, event.NewOrdersArchived(), nil)
+	archiveOrders.BatchSize = 2
+
+	output, err := archiveOrders.Execute(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, output.Archived)
+	assert.Equal(t, 2, output.Batches)
+	assert.Len(t, archiver.cutoffs, 3)
+}
+
+func TestGivenAFailingArchiver_WhenArchiveOrders_ThenShouldReturnTheError(t *testing.T) {
+	archiver := &archiverStub{err: errors.New("connection refused")}
+
+	output, err := NewArchiveOrdersUseCase(archiver, event.NewOrdersArchived(), nil).Execute(context.Background())
+
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 0, output.Archived)
+}
+
+func TestGivenANonPositiveMaxAge_WhenArchiveOrders_ThenShouldFailWithoutArchiving(t *testing.T) {
+	archiver := &archiverStub{remaining: 4}
+	archiveOrders := NewArchiveOrdersUseCase(archiver, event.NewOrdersArchived(), nil)
+	archiveOrders.MaxAge = 0
+
+	_, err := archiveOrders.Execute(context.Background())
+
+	assert.EqualError(t, err, "archive max age must be positive")
+	assert.Empty(t, archiver.cutoffs)
+}
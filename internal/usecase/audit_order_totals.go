@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"math"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+const (
+	DefaultAuditBatchSize = 500
+	// FinalPriceTolerance absorbs the rounding of the float price columns, so
+	// only totals that are off by at least half a cent are reported.
+	FinalPriceTolerance = 0.005
+)
+
+type OrderTotalMismatchDTO struct {
+	ID                 string  `json:"id"`
+	Price              float64 `json:"price"`
+	Tax                float64 `json:"tax"`
+	StoredFinalPrice   float64 `json:"stored_final_price"`
+	ExpectedFinalPrice float64 `json:"expected_final_price"`
+}
+
+type AuditOrderTotalsOutputDTO struct {
+	Scanned    int                     `json:"scanned"`
+	Mismatches []OrderTotalMismatchDTO `json:"mismatches"`
+}
+
+type AuditOrderTotalsUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	BatchSize       int
+}
+
+func NewAuditOrderTotalsUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+) *AuditOrderTotalsUseCase {
+	return &AuditOrderTotalsUseCase{
+		OrderRepository: OrderRepository,
+		BatchSize:       DefaultAuditBatchSize,
+	}
+}
+
+// Execute scans every order, BatchSize rows at a time, and reports those whose
+// stored final price is not price + tax.
+func (a *AuditOrderTotalsUseCase) Execute(ctx context.Context) (AuditOrderTotalsOutputDTO, error) {
+	batchSize := a.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultAuditBatchSize
+	}
+
+	output := AuditOrderTotalsOutputDTO{Mismatches: []OrderTotalMismatchDTO{}}
+	for offset := 0; ; offset += batchSize {
+		orders, err := a.OrderRepository.FindPage(ctx, batchSize, offset)
+		if err != nil {
+			return AuditOrderTotalsOutputDTO{}, err
+		}
+
+		for _, order := range orders {
+			expected := entity.RoundPrice(order.Price + order.Tax)
+			if math.Abs(order.FinalPrice-expected) >= FinalPriceTolerance {
+				output.Mismatches = append(output.Mismatches, OrderTotalMismatchDTO{
+					ID:                 order.ID,
+					Price:              order.Price,
+					Tax:                order.Tax,
+					StoredFinalPrice:   order.FinalPrice,
+					ExpectedFinalPrice: expected,
+				})
+			}
+		}
+		output.Scanned += len(orders)
+
+		if len(orders) < batchSize {
+			return output, nil
+		}
+	}
+}
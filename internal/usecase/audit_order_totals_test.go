@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenACorruptOrder_WhenAuditOrderTotals_ThenShouldFlagIt(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindPage", mock.Anything, 2, 0).Return([]entity.Order{
+		{ID: "ok", Price: 10, Tax: 2, FinalPrice: 12},
+		{ID: "corrupt", Price: 10, Tax: 2, FinalPrice: 15},
+	}, nil)
+	repository.On("FindPage", mock.Anything, 2, 2).Return([]entity.Order{
+		{ID: "rounded", Price: 100.5, Tax: 10.05, FinalPrice: 110.550003},
+	}, nil)
+	useCase := NewAuditOrderTotalsUseCase(repository)
+	useCase.BatchSize = 2
+
+	output, err := useCase.Execute(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, output.Scanned)
+	assert.Equal(t, []OrderTotalMismatchDTO{
+		{ID: "corrupt", Price: 10, Tax: 2, StoredFinalPrice: 15, ExpectedFinalPrice: 12},
+	}, output.Mismatches)
+	repository.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+)
+
+type BulkUpdateOrderStatusInputDTO struct {
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	CreatedBefore time.Time `json:"created_before"`
+}
+
+type BulkUpdateOrderStatusOutputDTO struct {
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	CreatedBefore time.Time `json:"created_before,omitzero"`
+	Affected      int       `json:"affected"`
+}
+
+type BulkUpdateOrderStatusUseCase struct {
+	OrderRepository     entity.OrderRepositoryInterface
+	OrdersStatusChanged events.EventInterface
+	EventDispatcher     events.EventDispatcherInterface
+}
+
+func NewBulkUpdateOrderStatusUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrdersStatusChanged events.EventInterface,
+	EventDispatcher events.EventDispatcherInterface,
+) *BulkUpdateOrderStatusUseCase {
+	if EventDispatcher == nil {
+		EventDispatcher = events.NewNoopDispatcher()
+	}
+	return &BulkUpdateOrderStatusUseCase{
+		OrderRepository:     OrderRepository,
+		OrdersStatusChanged: OrdersStatusChanged,
+		EventDispatcher:     EventDispatcher,
+	}
+}
+
+// Execute moves every order matching the input filter to ToStatus in one
+// update. It returns entity.ErrInvalidStatusTransition, without touching any
+// order, when FromStatus cannot move to ToStatus. A single OrdersStatusChanged
+// event is dispatched when at least one order was updated.
+func (b *BulkUpdateOrderStatusUseCase) Execute(ctx context.Context, input BulkUpdateOrderStatusInputDTO) (BulkUpdateOrderStatusOutputDTO, error) {
+	if !entity.CanTransitionOrderStatus(input.FromStatus, input.ToStatus) {
+		return BulkUpdateOrderStatusOutputDTO{}, entity.ErrInvalidStatusTransition
+	}
+
+	filter := entity.OrderStatusFilter{Status: input.FromStatus, CreatedBefore: input.CreatedBefore}
+	now := time.Now().UTC().Truncate(time.Second)
+	affected, err := b.OrderRepository.UpdateStatusWhere(ctx, filter, input.ToStatus, now)
+	if err != nil {
+		return BulkUpdateOrderStatusOutputDTO{}, err
+	}
+
+	output := BulkUpdateOrderStatusOutputDTO{
+		FromStatus:    input.FromStatus,
+		ToStatus:      input.ToStatus,
+		CreatedBefore: input.CreatedBefore,
+		Affected:      affected,
+	}
+	if affected > 0 {
+		statusChanged := events.NewFrom(b.OrdersStatusChanged)
+		statusChanged.SetPayload(output)
+		b.EventDispatcher.Dispatch(statusChanged)
+	}
+	return output, nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events/eventstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenAValidTransition_WhenBulkUpdateOrderStatus_ThenShouldReturnTheAffectedCountAndDispatchOnce(t *testing.T) {
+	cutoff := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	filter := entity.OrderStatusFilter{Status: entity.OrderStatusPending, CreatedBefore: cutoff}
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("UpdateStatusWhere", mock.Anything, filter, entity.OrderStatusCancelled, mock.Anything).Return(3, nil)
+	dispatcher := &eventstest.DispatcherMock{}
+	dispatcher.On("Dispatch", mock.Anything).Return(nil)
+
+	useCase := NewBulkUpdateOrderStatusUseCase(repository, event.NewOrdersStatusChanged(), dispatcher)
+	output, err := useCase.Execute(context.Background(), BulkUpdateOrderStatusInputDTO{
+		FromStatus:    entity.OrderStatusPending,
+		ToStatus:      entity.OrderStatusCancelled,
+		CreatedBefore: cutoff,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, output.Affected)
+	dispatcher.AssertNumberOfCalls(t, "Dispatch", 1)
+	dispatched := dispatcher.Calls[0].Arguments.Get(0).(events.EventInterface)
+	assert.Equal(t, "OrdersStatusChanged", dispatched.GetName())
+	assert.Equal(t, output, dispatched.GetPayload())
+}
+
+func TestGivenAnInvalidTransition_WhenBulkUpdateOrderStatus_ThenShouldNotUpdateAnyOrder(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	useCase := NewBulkUpdateOrderStatusUseCase(repository, event.NewOrdersStatusChanged(), nil)
+
+	for _, transition := range [][2]string{
+		{entity.OrderStatusDelivered, entity.OrderStatusPending},
+		{entity.OrderStatusPending, entity.OrderStatusShipped},
+		{entity.OrderStatusCancelled, entity.OrderStatusProcessing},
+		{"unknown", entity.OrderStatusCancelled},
+	} {
+		_, err := useCase.Execute(context.Background(), BulkUpdateOrderStatusInputDTO{FromStatus: transition[0], ToStatus: transition[1]})
+		entitytest.AssertDomainError(t, err, entity.ErrInvalidStatusTransition, transition)
+	}
+	repository.AssertNotCalled(t, "UpdateStatusWhere", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGivenNoMatchingOrders_WhenBulkUpdateOrderStatus_ThenShouldNotDispatch(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("UpdateStatusWhere", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	dispatcher := &eventstest.DispatcherMock{}
+
+	useCase := NewBulkUpdateOrderStatusUseCase(repository, event.NewOrdersStatusChanged(), dispatcher)
+	output, err := useCase.Execute(context.Background(), BulkUpdateOrderStatusInputDTO{FromStatus: entity.OrderStatusPending, ToStatus: entity.OrderStatusProcessing})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, output.Affected)
+	dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything)
+}
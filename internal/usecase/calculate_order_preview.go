@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+// previewOrderID stands in for the ID a preview does not have, so the order
+// passes the same validation a real create applies.
+const previewOrderID = "preview"
+
+type OrderPreviewInputDTO struct {
+	Price float64 `json:"price"`
+	Tax   float64 `json:"tax"`
+}
+
+type OrderPreviewOutputDTO struct {
+	Price      float64 `json:"price"`
+	Tax        float64 `json:"tax"`
+	FinalPrice float64 `json:"final_price"`
+}
+
+// CalculateOrderPreviewUseCase computes what creating an order would charge
+// without saving it or dispatching events.
+type CalculateOrderPreviewUseCase struct {
+	// Rules should match the CreateOrderUseCase the preview stands for.
+	Rules []entity.OrderRule
+}
+
+func NewCalculateOrderPreviewUseCase() *CalculateOrderPreviewUseCase {
+	return &CalculateOrderPreviewUseCase{}
+}
+
+// Execute returns the same validation and rule errors CreateOrderUseCase would.
+func (c *CalculateOrderPreviewUseCase) Execute(input OrderPreviewInputDTO) (OrderPreviewOutputDTO, error) {
+	order, err := newPendingOrder(OrderInputDTO{
+		ID:    previewOrderID,
+		Price: input.Price,
+		Tax:   input.Tax,
+	}, time.Now().UTC(), c.Rules)
+	if err != nil {
+		return OrderPreviewOutputDTO{}, err
+	}
+
+	return OrderPreviewOutputDTO{
+		Price:      order.Price,
+		Tax:        order.Tax,
+		FinalPrice: order.FinalPrice,
+	}, nil
+}
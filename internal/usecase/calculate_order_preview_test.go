@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenAnOrder_WhenPreviewed_ThenShouldMatchWhatCreateComputes(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), events.NewNoopDispatcher())
+	preview := NewCalculateOrderPreviewUseCase()
+
+	created, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: "123", Price: 10.005, Tax: 1.2})
+	assert.Nil(t, err)
+	previewed, err := preview.Execute(OrderPreviewInputDTO{Price: 10.005, Tax: 1.2})
+	assert.Nil(t, err)
+
+	assert.Equal(t, OrderPreviewOutputDTO{Price: created.Price, Tax: created.Tax, FinalPrice: created.FinalPrice}, previewed)
+	repository.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestGivenARuleViolation_WhenPreviewed_ThenShouldRejectItLikeCreate(t *testing.T) {
+	rules := []entity.OrderRule{entity.TaxNotAbovePrice}
+	createOrder := NewCreateOrderUseCase(&mocks.OrderRepositoryMock{}, event.NewOrderCreated(), events.NewNoopDispatcher())
+	createOrder.Rules = rules
+	preview := NewCalculateOrderPreviewUseCase()
+	preview.Rules = rules
+
+	_, createErr := createOrder.Execute(context.Background(), OrderInputDTO{ID: "123", Price: 10, Tax: 20})
+	_, previewErr := preview.Execute(OrderPreviewInputDTO{Price: 10, Tax: 20})
+
+	assert.ErrorIs(t, previewErr, entity.ErrTaxExceedsPrice)
+	assert.Equal(t, createErr, previewErr)
+}
@@ -1,6 +1,10 @@
 package usecase
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
 	"github.com/mvr-garcia/go-clean-arch/internal/entity"
 	"github.com/mvr-garcia/go-clean-arch/pkg/events"
 )
@@ -9,19 +13,86 @@ type OrderInputDTO struct {
 	ID    string  `json:"id"`
 	Price float64 `json:"price"`
 	Tax   float64 `json:"tax"`
+	// TraceID correlates the order with the request that created it. It is
+	// filled by the transport, never decoded from the request body.
+	TraceID string `json:"-"`
+}
+
+// Normalize returns input with its ID trimmed and checked by
+// entity.NormalizeOrderID against the column size and its price and tax
+// rounded to cents, so what is validated and stored is what the response
+// reports. Transports check ORDER_ID_MAX_LENGTH before calling a use case.
+func (input OrderInputDTO) Normalize() (OrderInputDTO, error) {
+	id, err := entity.NormalizeOrderID(input.ID, 0)
+	if err != nil {
+		return OrderInputDTO{}, err
+	}
+	input.ID = id
+	input.Price = entity.RoundPrice(input.Price)
+	input.Tax = entity.RoundPrice(input.Tax)
+	return input, nil
 }
 
 type OrderOutputDTO struct {
-	ID         string  `json:"id"`
-	Price      float64 `json:"price"`
-	Tax        float64 `json:"tax"`
-	FinalPrice float64 `json:"final_price"`
+	ID         string    `json:"id"`
+	Price      float64   `json:"price"`
+	Tax        float64   `json:"tax"`
+	FinalPrice float64   `json:"final_price"`
+	Status     string    `json:"status"`
+	CreatedAt  Timestamp `json:"created_at"`
+	UpdatedAt  Timestamp `json:"updated_at"`
+	TraceID    string    `json:"trace_id,omitempty"`
+}
+
+func newOrderOutputDTO(order entity.Order, timestampFormat string) OrderOutputDTO {
+	return OrderOutputDTO{
+		ID:         order.ID,
+		Price:      order.Price,
+		Tax:        order.Tax,
+		FinalPrice: entity.RoundPrice(order.Price + order.Tax),
+		Status:     order.Status,
+		CreatedAt:  NewTimestamp(order.CreatedAt, timestampFormat),
+		UpdatedAt:  NewTimestamp(order.UpdatedAt, timestampFormat),
+		TraceID:    order.TraceID,
+	}
+}
+
+// newPendingOrder builds the order the normalized input describes, computes
+// its final price and checks it against rules. Every path that creates orders
+// goes through it, as does the preview, so they all agree on what is accepted.
+func newPendingOrder(input OrderInputDTO, now time.Time, rules []entity.OrderRule) (entity.Order, error) {
+	input, err := input.Normalize()
+	if err != nil {
+		return entity.Order{}, err
+	}
+	order := entity.Order{
+		ID:        input.ID,
+		Price:     input.Price,
+		Tax:       input.Tax,
+		Status:    entity.OrderStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+		TraceID:   input.TraceID,
+	}
+	if err := order.CalculateFinalPrice(); err != nil {
+		return entity.Order{}, err
+	}
+	if err := entity.EvaluateOrderRules(order, rules); err != nil {
+		return entity.Order{}, err
+	}
+	return order, nil
 }
 
 type CreateOrderUseCase struct {
 	OrderRepository entity.OrderRepositoryInterface
 	OrderCreated    events.EventInterface
 	EventDispatcher events.EventDispatcherInterface
+	Logger          *slog.Logger
+	// Rules are evaluated before the order is saved; any violation rejects it.
+	Rules []entity.OrderRule
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
 }
 
 func NewCreateOrderUseCase(
@@ -29,33 +100,38 @@ func NewCreateOrderUseCase(
 	OrderCreated events.EventInterface,
 	EventDispatcher events.EventDispatcherInterface,
 ) *CreateOrderUseCase {
+	if EventDispatcher == nil {
+		EventDispatcher = events.NewNoopDispatcher()
+	}
 	return &CreateOrderUseCase{
 		OrderRepository: OrderRepository,
 		OrderCreated:    OrderCreated,
 		EventDispatcher: EventDispatcher,
+		Logger:          slog.Default(),
 	}
 }
 
-func (c *CreateOrderUseCase) Execute(input OrderInputDTO) (OrderOutputDTO, error) {
-	order := entity.Order{
-		ID:    input.ID,
-		Price: input.Price,
-		Tax:   input.Tax,
+func (c *CreateOrderUseCase) Execute(ctx context.Context, input OrderInputDTO) (OrderOutputDTO, error) {
+	order, err := newPendingOrder(input, time.Now().UTC().Truncate(time.Second), c.Rules)
+	if err != nil {
+		return OrderOutputDTO{}, err
 	}
-	order.CalculateFinalPrice()
-	if err := c.OrderRepository.Save(&order); err != nil {
+	if err := c.OrderRepository.Save(ctx, &order); err != nil {
 		return OrderOutputDTO{}, err
 	}
 
-	dto := OrderOutputDTO{
-		ID:         order.ID,
-		Price:      order.Price,
-		Tax:        order.Tax,
-		FinalPrice: order.Price + order.Tax,
+	dto := newOrderOutputDTO(order, c.TimestampFormat)
+
+	if c.Logger != nil {
+		c.Logger.Info("order created", "order_id", order.ID, "trace_id", order.TraceID)
 	}
 
-	c.OrderCreated.SetPayload(dto)
-	c.EventDispatcher.Dispatch(c.OrderCreated)
+	if c.EventDispatcher == nil {
+		return dto, nil
+	}
+	orderCreated := events.NewFrom(c.OrderCreated)
+	orderCreated.SetPayload(dto)
+	c.EventDispatcher.Dispatch(orderCreated)
 
 	return dto, nil
 }
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events/eventstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenANoopDispatcher_WhenCreateOrder_ThenShouldCreateOrder(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), events.NewNoopDispatcher())
+	output, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: "123", Price: 10.0, Tax: 2.0})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "123", output.ID)
+	assert.Equal(t, 12.0, output.FinalPrice)
+	repository.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestGivenANilDispatcher_WhenCreateOrder_ThenShouldNotPanic(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil)
+	assert.NotPanics(t, func() {
+		_, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: "123", Price: 10.0, Tax: 2.0})
+		assert.Nil(t, err)
+	})
+}
+
+func TestGivenAValidOrder_WhenCreateOrder_ThenShouldDispatchOrderCreated(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+	dispatcher := &eventstest.DispatcherMock{}
+	dispatcher.On("Dispatch", mock.MatchedBy(func(e events.EventInterface) bool {
+		return e.GetName() == "OrderCreated"
+	})).Return(nil)
+
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), dispatcher)
+	output, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: "123", Price: 10.0, Tax: 2.0})
+
+	assert.Nil(t, err)
+	dispatcher.AssertNumberOfCalls(t, "Dispatch", 1)
+	dispatched := dispatcher.Calls[0].Arguments.Get(0).(events.EventInterface)
+	assert.Equal(t, output, dispatched.GetPayload())
+}
+
+type PayloadRecorder struct {
+	Payloads []interface{}
+}
+
+func (h *PayloadRecorder) Handle(event events.EventInterface, wg *sync.WaitGroup) {
+	defer wg.Done()
+	h.Payloads = append(h.Payloads, event.GetPayload())
+}
+
+// EventRecorder records the ID and payload of every event it handles. It is
+// safe for concurrent dispatches.
+type EventRecorder struct {
+	mu       sync.Mutex
+	Payloads map[string]interface{}
+}
+
+func (h *EventRecorder) Handle(event events.EventInterface, wg *sync.WaitGroup) {
+	defer wg.Done()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Payloads[event.(events.IdentifiedEventInterface).GetEventID()] = event.GetPayload()
+}
+
+func TestGivenConcurrentCreates_WhenDispatchingOrderCreated_ThenEachEventShouldCarryItsOwnIDAndPayload(t *testing.T) {
+	const creates = 50
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+	dispatcher := events.NewEventDispatcher()
+	recorder := &EventRecorder{Payloads: make(map[string]interface{})}
+	assert.Nil(t, dispatcher.Register("OrderCreated", recorder))
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), dispatcher)
+	createOrder.Logger = nil
+
+	var wg sync.WaitGroup
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: fmt.Sprintf("order-%d", i), Price: 10, Tax: 2})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, recorder.Payloads, creates)
+	ids := make(map[string]bool)
+	for _, payload := range recorder.Payloads {
+		ids[payload.(OrderOutputDTO).ID] = true
+	}
+	assert.Len(t, ids, creates)
+}
+
+func TestGivenATraceID_WhenCreateOrder_ThenShouldCorrelateOrderLogAndEvent(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+	recorder := &PayloadRecorder{}
+	dispatcher := events.NewEventDispatcher()
+	dispatcher.Register("OrderCreated", recorder)
+	var logs bytes.Buffer
+
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), dispatcher)
+	createOrder.Logger = slog.New(slog.NewJSONHandler(&logs, nil))
+	_, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: "123", Price: 10.0, Tax: 2.0, TraceID: "host/abc-000001"})
+	assert.Nil(t, err)
+
+	saved := repository.Calls[0].Arguments.Get(1).(*entity.Order)
+	assert.Equal(t, "host/abc-000001", saved.TraceID)
+
+	var logLine map[string]interface{}
+	assert.Nil(t, json.Unmarshal(logs.Bytes(), &logLine))
+	assert.Equal(t, "host/abc-000001", logLine["trace_id"])
+
+	assert.Len(t, recorder.Payloads, 1)
+	assert.Equal(t, "host/abc-000001", recorder.Payloads[0].(OrderOutputDTO).TraceID)
+}
+
+func TestGivenPricesWithFloatNoise_WhenCreateOrder_ThenShouldReturnARoundedFinalPrice(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), events.NewNoopDispatcher())
+	output, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: "123", Price: 0.1, Tax: 0.2})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0.3, output.FinalPrice)
+	saved := repository.Calls[0].Arguments.Get(1).(*entity.Order)
+	assert.Equal(t, 0.3, saved.FinalPrice)
+}
+
+func TestGivenOrderRules_WhenCreateOrder_ThenShouldRejectViolationsWithoutSaving(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+	createOrder := NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil)
+	createOrder.Rules = []entity.OrderRule{entity.TaxNotAbovePrice, entity.RequireApprovalAbove(1000)}
+
+	_, err := createOrder.Execute(context.Background(), OrderInputDTO{ID: "ok", Price: 100, Tax: 10})
+	assert.Nil(t, err)
+
+	_, err = createOrder.Execute(context.Background(), OrderInputDTO{ID: "taxed", Price: 100, Tax: 150})
+	entitytest.AssertDomainError(t, err, entity.ErrTaxExceedsPrice)
+	repository.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestGivenAZeroPrice_WhenCreateOrder_ThenShouldRejectItWithoutSaving(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+
+	_, err := NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil).
+		Execute(context.Background(), OrderInputDTO{ID: "123", Price: 0, Tax: 2.0})
+
+	entitytest.AssertDomainError(t, err, entity.ErrInvalidPrice)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestGivenAnInputWithWhitespaceAndSubCentPrices_WhenNormalize_ThenShouldTrimAndRoundIt(t *testing.T) {
+	input, err := OrderInputDTO{ID: "  123\t", Price: 10.004, Tax: 1.005, TraceID: "host/abc"}.Normalize()
+
+	assert.Nil(t, err)
+	assert.Equal(t, OrderInputDTO{ID: "123", Price: 10.0, Tax: 1.01, TraceID: "host/abc"}, input)
+
+	_, err = OrderInputDTO{ID: "   ", Price: 10, Tax: 1}.Normalize()
+	entitytest.AssertDomainError(t, err, entity.ErrInvalidID)
+}
+
+func TestGivenAnUnnormalizedInput_WhenCreateOrder_ThenShouldStoreAndReturnTheNormalizedOrder(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	output, err := NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil).
+		Execute(context.Background(), OrderInputDTO{ID: " 123 ", Price: 10.004, Tax: 1.005})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "123", output.ID)
+	assert.Equal(t, 10.0, output.Price)
+	assert.Equal(t, 1.01, output.Tax)
+	assert.Equal(t, 11.01, output.FinalPrice)
+	saved := repository.Calls[0].Arguments.Get(1).(*entity.Order)
+	assert.Equal(t, "123", saved.ID)
+	assert.Equal(t, 10.0, saved.Price)
+	assert.Equal(t, 1.01, saved.Tax)
+}
+
+func TestGivenAPriceThatRoundsToZero_WhenCreateOrder_ThenShouldRejectIt(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+
+	_, err := NewCreateOrderUseCase(repository, event.NewOrderCreated(), nil).
+		Execute(context.Background(), OrderInputDTO{ID: "123", Price: 0.004, Tax: 2.0})
+
+	entitytest.AssertDomainError(t, err, entity.ErrInvalidPrice)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+)
+
+type CreateOrdersBatchInputDTO struct {
+	Orders []OrderInputDTO `json:"orders"`
+}
+
+type BatchItemErrorDTO struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+type CreateOrdersBatchOutputDTO struct {
+	Orders []OrderOutputDTO    `json:"orders"`
+	Errors []BatchItemErrorDTO `json:"errors,omitempty"`
+}
+
+type CreateOrdersBatchUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	OrderCreated    events.EventInterface
+	EventDispatcher events.EventDispatcherInterface
+	// AllOrNothing persists the whole batch in one transaction and fails on the
+	// first invalid item. Otherwise each valid item is saved on its own and
+	// failures are reported per index in the output.
+	AllOrNothing bool
+	// MaxBatchSize rejects larger batches before any order is processed. Zero
+	// means unlimited.
+	MaxBatchSize int
+	// Rules are evaluated for every item, like CreateOrderUseCase does.
+	Rules []entity.OrderRule
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewCreateOrdersBatchUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderCreated events.EventInterface,
+	EventDispatcher events.EventDispatcherInterface,
+	AllOrNothing bool,
+) *CreateOrdersBatchUseCase {
+	if EventDispatcher == nil {
+		EventDispatcher = events.NewNoopDispatcher()
+	}
+	return &CreateOrdersBatchUseCase{
+		OrderRepository: OrderRepository,
+		OrderCreated:    OrderCreated,
+		EventDispatcher: EventDispatcher,
+		AllOrNothing:    AllOrNothing,
+	}
+}
+
+func (c *CreateOrdersBatchUseCase) Execute(ctx context.Context, input CreateOrdersBatchInputDTO) (CreateOrdersBatchOutputDTO, error) {
+	if c.MaxBatchSize > 0 && len(input.Orders) > c.MaxBatchSize {
+		return CreateOrdersBatchOutputDTO{}, fmt.Errorf("%w: %d, the maximum is %d", entity.ErrBatchTooLarge, len(input.Orders), c.MaxBatchSize)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	orders := make([]*entity.Order, 0, len(input.Orders))
+	output := CreateOrdersBatchOutputDTO{Orders: []OrderOutputDTO{}}
+
+	for i, item := range input.Orders {
+		pending, err := newPendingOrder(item, now, c.Rules)
+		if err != nil {
+			if c.AllOrNothing {
+				return CreateOrdersBatchOutputDTO{}, &entity.BatchItemError{Index: i, Err: err}
+			}
+			output.Errors = append(output.Errors, BatchItemErrorDTO{Index: i, Error: err.Error()})
+			continue
+		}
+		order := &pending
+		if !c.AllOrNothing {
+			if err := c.OrderRepository.Save(ctx, order); err != nil {
+				output.Errors = append(output.Errors, BatchItemErrorDTO{Index: i, Error: err.Error()})
+				continue
+			}
+		}
+		orders = append(orders, order)
+	}
+
+	if c.AllOrNothing {
+		if err := c.OrderRepository.SaveBatch(ctx, orders); err != nil {
+			return CreateOrdersBatchOutputDTO{}, err
+		}
+	}
+
+	for _, order := range orders {
+		dto := newOrderOutputDTO(*order, c.TimestampFormat)
+		output.Orders = append(output.Orders, dto)
+		orderCreated := events.NewFrom(c.OrderCreated)
+		orderCreated.SetPayload(dto)
+		c.EventDispatcher.Dispatch(orderCreated)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBatchInput() CreateOrdersBatchInputDTO {
+	return CreateOrdersBatchInputDTO{Orders: []OrderInputDTO{
+		{ID: "a", Price: 10.0, Tax: 2.0},
+		{ID: "b", Price: 20.0, Tax: 4.0},
+		{ID: "c", Price: 0, Tax: 1.0},
+	}}
+}
+
+func TestGivenAnInvalidThirdItem_WhenCreateOrdersBatchAllOrNothing_ThenShouldPersistNothingAndReportTheIndex(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+
+	createBatch := NewCreateOrdersBatchUseCase(repository, event.NewOrderCreated(), nil, true)
+	_, err := createBatch.Execute(context.Background(), newBatchInput())
+
+	var itemErr *entity.BatchItemError
+	assert.True(t, errors.As(err, &itemErr))
+	assert.Equal(t, 2, itemErr.Index)
+	entitytest.AssertDomainError(t, itemErr.Err, entity.ErrInvalidPrice)
+	repository.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestGivenARepositoryFailure_WhenCreateOrdersBatchAllOrNothing_ThenShouldSurfaceTheFailingIndex(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("SaveBatch", mock.Anything, mock.Anything).
+		Return(&entity.BatchItemError{Index: 1, Err: errors.New("duplicate entry")})
+	input := newBatchInput()
+	input.Orders = input.Orders[:2]
+
+	createBatch := NewCreateOrdersBatchUseCase(repository, event.NewOrderCreated(), nil, true)
+	_, err := createBatch.Execute(context.Background(), input)
+
+	assert.EqualError(t, err, "item 1: duplicate entry")
+}
+
+func TestGivenAnInvalidThirdItem_WhenCreateOrdersBatchBestEffort_ThenShouldSaveTheOthers(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	createBatch := NewCreateOrdersBatchUseCase(repository, event.NewOrderCreated(), nil, false)
+	output, err := createBatch.Execute(context.Background(), newBatchInput())
+
+	assert.Nil(t, err)
+	assert.Len(t, output.Orders, 2)
+	assert.Equal(t, []BatchItemErrorDTO{{Index: 2, Error: "invalid price"}}, output.Errors)
+	repository.AssertNumberOfCalls(t, "Save", 2)
+}
+
+func TestGivenAMaxBatchSize_WhenCreateOrdersBatch_ThenShouldRejectOnlyLargerBatches(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("SaveBatch", mock.Anything, mock.Anything).Return(nil)
+	input := newBatchInput()
+	input.Orders = input.Orders[:2]
+	createBatch := NewCreateOrdersBatchUseCase(repository, event.NewOrderCreated(), nil, true)
+	createBatch.MaxBatchSize = 2
+
+	output, err := createBatch.Execute(context.Background(), input)
+	assert.Nil(t, err)
+	assert.Len(t, output.Orders, 2)
+
+	input.Orders = append(input.Orders, OrderInputDTO{ID: "d", Price: 10.0, Tax: 2.0})
+	_, err = createBatch.Execute(context.Background(), input)
+	entitytest.AssertDomainError(t, err, entity.ErrBatchTooLarge)
+	assert.EqualError(t, err, "batch has too many orders: 3, the maximum is 2")
+	repository.AssertNumberOfCalls(t, "SaveBatch", 1)
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+)
+
+type DuplicateOrderInputDTO struct {
+	// SourceID is the order to copy, taken from the request path.
+	SourceID string `json:"-"`
+	// ID is the new order's ID. A UUID is generated when empty.
+	ID      string `json:"id"`
+	TraceID string `json:"-"`
+}
+
+type DuplicateOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	CreateOrder     *CreateOrderUseCase
+}
+
+func NewDuplicateOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+	OrderCreated events.EventInterface,
+	EventDispatcher events.EventDispatcherInterface,
+) *DuplicateOrderUseCase {
+	return &DuplicateOrderUseCase{
+		OrderRepository: OrderRepository,
+		CreateOrder:     NewCreateOrderUseCase(OrderRepository, OrderCreated, EventDispatcher),
+	}
+}
+
+// Execute copies the price and tax of the source order into a new pending
+// order with fresh timestamps. It is created like any other order, so
+// OrderCreated is dispatched for it. It returns entity.ErrOrderNotFound when
+// the source order does not exist.
+func (d *DuplicateOrderUseCase) Execute(ctx context.Context, input DuplicateOrderInputDTO) (OrderOutputDTO, error) {
+	source, err := d.OrderRepository.FindByID(ctx, input.SourceID)
+	if err != nil {
+		return OrderOutputDTO{}, err
+	}
+
+	id := input.ID
+	if id == "" {
+		id = uuid.NewString()
+	}
+	return d.CreateOrder.Execute(ctx, OrderInputDTO{
+		ID:      id,
+		Price:   source.Price,
+		Tax:     source.Tax,
+		TraceID: input.TraceID,
+	})
+}
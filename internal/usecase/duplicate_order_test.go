@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events/eventstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenAShippedOrder_WhenDuplicateOrder_ThenShouldCreateAPendingCopyWithANewID(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindByID", mock.Anything, "source").Return(&entity.Order{
+		ID: "source", Price: 10, Tax: 2, FinalPrice: 12, Status: entity.OrderStatusShipped,
+		CreatedAt: createdAt, UpdatedAt: createdAt, TraceID: "old-trace",
+	}, nil)
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+	dispatcher := &eventstest.DispatcherMock{}
+	dispatcher.On("Dispatch", mock.Anything).Return(nil)
+
+	useCase := NewDuplicateOrderUseCase(repository, event.NewOrderCreated(), dispatcher)
+	output, err := useCase.Execute(context.Background(), DuplicateOrderInputDTO{SourceID: "source", TraceID: "new-trace"})
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, output.ID)
+	assert.NotEqual(t, "source", output.ID)
+	assert.Equal(t, 10.0, output.Price)
+	assert.Equal(t, 2.0, output.Tax)
+	assert.Equal(t, 12.0, output.FinalPrice)
+	assert.Equal(t, entity.OrderStatusPending, output.Status)
+	assert.True(t, output.CreatedAt.Time().After(createdAt))
+	assert.Equal(t, output.CreatedAt, output.UpdatedAt)
+	assert.Equal(t, "new-trace", output.TraceID)
+	dispatched := dispatcher.Calls[0].Arguments.Get(0).(events.EventInterface)
+	assert.Equal(t, "OrderCreated", dispatched.GetName())
+}
+
+func TestGivenAnExplicitID_WhenDuplicateOrder_ThenShouldUseIt(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindByID", mock.Anything, "source").Return(&entity.Order{ID: "source", Price: 10, Tax: 2}, nil)
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	output, err := NewDuplicateOrderUseCase(repository, event.NewOrderCreated(), nil).
+		Execute(context.Background(), DuplicateOrderInputDTO{SourceID: "source", ID: "reorder-1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "reorder-1", output.ID)
+}
+
+func TestGivenAMissingSource_WhenDuplicateOrder_ThenShouldReturnNotFound(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindByID", mock.Anything, "missing").Return(nil, entity.ErrOrderNotFound)
+
+	_, err := NewDuplicateOrderUseCase(repository, event.NewOrderCreated(), nil).
+		Execute(context.Background(), DuplicateOrderInputDTO{SourceID: "missing"})
+
+	entitytest.AssertDomainError(t, err, entity.ErrOrderNotFound)
+	repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
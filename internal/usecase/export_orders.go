@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+type ExportOrdersInputDTO struct {
+	// Fields selects the fields written for every order, from
+	// entity.OrderExportFields. Empty exports all of them.
+	Fields []string
+}
+
+type ExportOrdersUseCase struct {
+	OrderExporter entity.OrderExporter
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewExportOrdersUseCase(OrderExporter entity.OrderExporter) *ExportOrdersUseCase {
+	return &ExportOrdersUseCase{OrderExporter: OrderExporter}
+}
+
+// Execute streams the orders to w as newline-delimited JSON. The fields are
+// validated before anything is written, so an invalid list fails with
+// entity.ErrInvalidExportField and leaves w untouched. Timestamps use
+// TimestampFormat, like every other response.
+func (e *ExportOrdersUseCase) Execute(ctx context.Context, input ExportOrdersInputDTO, w io.Writer) error {
+	if err := entity.ValidateExportFields(input.Fields); err != nil {
+		return err
+	}
+	layout := e.TimestampFormat
+	if layout == "" {
+		layout = DefaultTimestampFormat
+	}
+	return e.OrderExporter.Export(ctx, w, layout, input.Fields...)
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
+	"github.com/stretchr/testify/assert"
+)
+
+type exporterRecorder struct {
+	timeLayout string
+	fields     []string
+	calls      int
+}
+
+func (e *exporterRecorder) Export(ctx context.Context, w io.Writer, timeLayout string, fields ...string) error {
+	e.timeLayout = timeLayout
+	e.fields = fields
+	e.calls++
+	return nil
+}
+
+func TestGivenKnownFields_WhenExportOrders_ThenShouldExportOnlyThoseFields(t *testing.T) {
+	exporter := &exporterRecorder{}
+
+	err := NewExportOrdersUseCase(exporter).Execute(context.Background(), ExportOrdersInputDTO{Fields: []string{"id", "final_price"}}, io.Discard)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "final_price"}, exporter.fields)
+	assert.Equal(t, DefaultTimestampFormat, exporter.timeLayout)
+}
+
+func TestGivenAnUnknownOrRepeatedField_WhenExportOrders_ThenShouldRejectItBeforeExporting(t *testing.T) {
+	exporter := &exporterRecorder{}
+	exportOrders := NewExportOrdersUseCase(exporter)
+
+	err := exportOrders.Execute(context.Background(), ExportOrdersInputDTO{Fields: []string{"id", "secret"}}, io.Discard)
+	entitytest.AssertDomainError(t, err, entity.ErrInvalidExportField)
+	assert.EqualError(t, err, `invalid export field: unknown field "secret"`)
+
+	err = exportOrders.Execute(context.Background(), ExportOrdersInputDTO{Fields: []string{"id", "id"}}, io.Discard)
+	entitytest.AssertDomainError(t, err, entity.ErrInvalidExportField)
+
+	assert.Equal(t, 0, exporter.calls)
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+type MostExpensiveOrderOutputDTO struct {
+	// Order is nil when there are no orders.
+	Order *OrderOutputDTO `json:"order"`
+}
+
+type GetMostExpensiveOrderUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewGetMostExpensiveOrderUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+) *GetMostExpensiveOrderUseCase {
+	return &GetMostExpensiveOrderUseCase{
+		OrderRepository: OrderRepository,
+	}
+}
+
+// Execute returns the order with the highest final price.
+func (g *GetMostExpensiveOrderUseCase) Execute(ctx context.Context) (MostExpensiveOrderOutputDTO, error) {
+	order, err := g.OrderRepository.FindMostExpensive(ctx)
+	if errors.Is(err, entity.ErrOrderNotFound) {
+		return MostExpensiveOrderOutputDTO{}, nil
+	}
+	if err != nil {
+		return MostExpensiveOrderOutputDTO{}, err
+	}
+
+	dto := newOrderOutputDTO(*order, g.TimestampFormat)
+	return MostExpensiveOrderOutputDTO{Order: &dto}, nil
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenOrders_WhenGetMostExpensiveOrder_ThenShouldReturnIt(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindMostExpensive", mock.Anything).Return(&entity.Order{ID: "top", Price: 100, Tax: 10, FinalPrice: 110}, nil)
+
+	output, err := NewGetMostExpensiveOrderUseCase(repository).Execute(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "top", output.Order.ID)
+	assert.Equal(t, 110.0, output.Order.FinalPrice)
+}
+
+func TestGivenNoOrders_WhenGetMostExpensiveOrder_ThenShouldReturnNoOrder(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindMostExpensive", mock.Anything).Return(nil, entity.ErrOrderNotFound)
+
+	output, err := NewGetMostExpensiveOrderUseCase(repository).Execute(context.Background())
+
+	assert.Nil(t, err)
+	assert.Nil(t, output.Order)
+}
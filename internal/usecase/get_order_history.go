@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+type OrderChangeDTO struct {
+	Action    string    `json:"action"`
+	Status    string    `json:"status"`
+	ChangedAt Timestamp `json:"changed_at"`
+}
+
+type OrderHistoryOutputDTO struct {
+	OrderID string           `json:"order_id"`
+	Changes []OrderChangeDTO `json:"changes"`
+}
+
+type GetOrderHistoryUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewGetOrderHistoryUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+) *GetOrderHistoryUseCase {
+	return &GetOrderHistoryUseCase{
+		OrderRepository: OrderRepository,
+	}
+}
+
+// Execute returns the changes recorded for the order, oldest first. Orders
+// created before the audit trail existed have an empty history; an unknown ID
+// returns entity.ErrOrderNotFound.
+func (g *GetOrderHistoryUseCase) Execute(ctx context.Context, id string) (OrderHistoryOutputDTO, error) {
+	changes, err := g.OrderRepository.FindHistory(ctx, id)
+	if err != nil {
+		return OrderHistoryOutputDTO{}, err
+	}
+	if len(changes) == 0 {
+		if _, err := g.OrderRepository.FindByID(ctx, id); err != nil {
+			return OrderHistoryOutputDTO{}, err
+		}
+	}
+
+	output := OrderHistoryOutputDTO{OrderID: id, Changes: make([]OrderChangeDTO, 0, len(changes))}
+	for _, change := range changes {
+		output.Changes = append(output.Changes, OrderChangeDTO{
+			Action:    change.Action,
+			Status:    change.Status,
+			ChangedAt: NewTimestamp(change.ChangedAt, g.TimestampFormat),
+		})
+	}
+	return output, nil
+}
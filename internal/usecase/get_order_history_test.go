@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/entity/entitytest"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenRecordedChanges_WhenGetOrderHistory_ThenShouldReturnThemInOrder(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindHistory", mock.Anything, "123").Return([]entity.OrderChange{
+		{OrderID: "123", Action: entity.OrderChangeCreated, Status: entity.OrderStatusPending, ChangedAt: createdAt},
+		{OrderID: "123", Action: entity.OrderChangeStatusChanged, Status: entity.OrderStatusProcessing, ChangedAt: createdAt.Add(time.Hour)},
+	}, nil)
+
+	output, err := NewGetOrderHistoryUseCase(repository).Execute(context.Background(), "123")
+
+	assert.Nil(t, err)
+	assert.Equal(t, OrderHistoryOutputDTO{
+		OrderID: "123",
+		Changes: []OrderChangeDTO{
+			{Action: entity.OrderChangeCreated, Status: entity.OrderStatusPending, ChangedAt: NewTimestamp(createdAt, "")},
+			{Action: entity.OrderChangeStatusChanged, Status: entity.OrderStatusProcessing, ChangedAt: NewTimestamp(createdAt.Add(time.Hour), "")},
+		},
+	}, output)
+	repository.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestGivenAnUnknownOrder_WhenGetOrderHistory_ThenShouldReturnNotFound(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindHistory", mock.Anything, "missing").Return([]entity.OrderChange(nil), nil)
+	repository.On("FindByID", mock.Anything, "missing").Return(nil, entity.ErrOrderNotFound)
+
+	_, err := NewGetOrderHistoryUseCase(repository).Execute(context.Background(), "missing")
+
+	entitytest.AssertDomainError(t, err, entity.ErrOrderNotFound)
+}
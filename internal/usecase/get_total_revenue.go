@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+)
+
+// DefaultRevenueCacheTTL is the TTL of new GetTotalRevenueUseCases.
+const DefaultRevenueCacheTTL = 10 * time.Second
+
+type TotalRevenueOutputDTO struct {
+	TotalRevenue float64   `json:"total_revenue"`
+	ComputedAt   Timestamp `json:"computed_at"`
+}
+
+// GetTotalRevenueUseCase caches the total revenue for TTL. It is an event
+// handler too: registered for the events of every change to the orders, it
+// drops the cached total as soon as one is dispatched, so the TTL only bounds
+// staleness when events are disabled.
+type GetTotalRevenueUseCase struct {
+	RevenueReader entity.OrderRevenueReader
+	TTL           time.Duration
+	// TimestampFormat lays out ComputedAt; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+
+	now func() time.Time
+
+	mu         sync.Mutex
+	cached     *TotalRevenueOutputDTO
+	generation uint64
+}
+
+var _ events.EventHandlerInterface = (*GetTotalRevenueUseCase)(nil)
+
+func NewGetTotalRevenueUseCase(RevenueReader entity.OrderRevenueReader) *GetTotalRevenueUseCase {
+	return &GetTotalRevenueUseCase{
+		RevenueReader: RevenueReader,
+		TTL:           DefaultRevenueCacheTTL,
+		now:           time.Now,
+	}
+}
+
+// Execute returns the cached total while it is younger than TTL and sums the
+// orders otherwise. The sum runs without holding the lock; a total that was
+// invalidated while it was being computed is returned but not cached.
+func (g *GetTotalRevenueUseCase) Execute(ctx context.Context) (TotalRevenueOutputDTO, error) {
+	g.mu.Lock()
+	if g.cached != nil && g.now().Sub(g.cached.ComputedAt.Time()) < g.TTL {
+		output := *g.cached
+		g.mu.Unlock()
+		return output, nil
+	}
+	generation := g.generation
+	g.mu.Unlock()
+
+	computedAt := g.now().UTC()
+	total, err := g.RevenueReader.TotalRevenue(ctx)
+	if err != nil {
+		return TotalRevenueOutputDTO{}, err
+	}
+	output := TotalRevenueOutputDTO{TotalRevenue: total, ComputedAt: NewTimestamp(computedAt, g.TimestampFormat)}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.generation == generation {
+		g.cached = &output
+	}
+	return output, nil
+}
+
+// Invalidate drops the cached total, so the next Execute sums the orders.
+func (g *GetTotalRevenueUseCase) Invalidate() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cached = nil
+	g.generation++
+}
+
+// Handle invalidates the cached total on any event it is registered for.
+func (g *GetTotalRevenueUseCase) Handle(event events.EventInterface, wg *sync.WaitGroup) {
+	defer wg.Done()
+	g.Invalidate()
+}
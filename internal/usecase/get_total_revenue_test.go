@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/event"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type revenueReaderStub struct {
+	total   float64
+	queries int
+	err     error
+}
+
+func (r *revenueReaderStub) TotalRevenue(ctx context.Context) (float64, error) {
+	r.queries++
+	return r.total, r.err
+}
+
+func newTestTotalRevenue(reader *revenueReaderStub, now *time.Time) *GetTotalRevenueUseCase {
+	getTotalRevenue := NewGetTotalRevenueUseCase(reader)
+	getTotalRevenue.TTL = 10 * time.Second
+	getTotalRevenue.now = func() time.Time { return *now }
+	return getTotalRevenue
+}
+
+func TestGivenACachedTotal_WhenGetTotalRevenueWithinTheTTL_ThenShouldNotQueryAgain(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	reader := &revenueReaderStub{total: 120.5}
+	getTotalRevenue := newTestTotalRevenue(reader, &now)
+
+	first, err := getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+	reader.total = 999
+	now = now.Add(9 * time.Second)
+	second, err := getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, reader.queries)
+	assert.Equal(t, 120.5, second.TotalRevenue)
+	assert.Equal(t, first, second)
+
+	now = now.Add(time.Second)
+	expired, err := getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reader.queries)
+	assert.Equal(t, 999.0, expired.TotalRevenue)
+}
+
+func TestGivenACachedTotal_WhenAnOrderIsCreated_ThenShouldQueryAgain(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	reader := &revenueReaderStub{total: 120.5}
+	getTotalRevenue := newTestTotalRevenue(reader, &now)
+	_, err := getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+	dispatcher := events.NewEventDispatcher()
+	assert.Nil(t, dispatcher.Register("OrderCreated", getTotalRevenue))
+	_, err = NewCreateOrderUseCase(repository, event.NewOrderCreated(), dispatcher).Execute(context.Background(), OrderInputDTO{ID: "123", Price: 10, Tax: 2})
+	assert.Nil(t, err)
+	reader.total = 132.5
+
+	output, err := getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reader.queries)
+	assert.Equal(t, 132.5, output.TotalRevenue)
+}
+
+func TestGivenAFailingReader_WhenGetTotalRevenue_ThenShouldNotCacheTheFailure(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	reader := &revenueReaderStub{err: errors.New("connection refused")}
+	getTotalRevenue := newTestTotalRevenue(reader, &now)
+
+	_, err := getTotalRevenue.Execute(context.Background())
+	assert.EqualError(t, err, "connection refused")
+
+	reader.err = nil
+	_, err = getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reader.queries)
+}
+
+func TestGivenATotalBeingComputed_WhenInvalidated_ThenShouldReturnItWithoutCachingIt(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	reader := &invalidatingReader{}
+	getTotalRevenue := NewGetTotalRevenueUseCase(reader)
+	getTotalRevenue.now = func() time.Time { return now }
+	reader.getTotalRevenue = getTotalRevenue
+
+	_, err := getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+	_, err = getTotalRevenue.Execute(context.Background())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, reader.queries)
+}
+
+// invalidatingReader invalidates the cache while the total is being summed,
+// as an order created concurrently would.
+type invalidatingReader struct {
+	getTotalRevenue *GetTotalRevenueUseCase
+	queries         int
+}
+
+func (r *invalidatingReader) TotalRevenue(ctx context.Context) (float64, error) {
+	r.queries++
+	if r.queries == 1 {
+		r.getTotalRevenue.Invalidate()
+	}
+	return 10, nil
+}
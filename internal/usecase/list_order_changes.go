@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+// ErrInvalidCursor is returned by ParseOrderChangeCursor for a cursor it did
+// not issue.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type ListOrderChangesUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewListOrderChangesUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+) *ListOrderChangesUseCase {
+	return &ListOrderChangesUseCase{
+		OrderRepository: OrderRepository,
+	}
+}
+
+// Execute lists up to limit orders after the cursor, least recently updated
+// first, for incremental sync. A full page carries the cursor of the next one.
+func (l *ListOrderChangesUseCase) Execute(ctx context.Context, after entity.OrderChangeCursor, limit int) (ListOrdersOutputDTO, error) {
+	orders, err := l.OrderRepository.FindUpdatedSince(ctx, after, limit)
+	if err != nil {
+		return ListOrdersOutputDTO{}, err
+	}
+
+	output := newListOrdersOutputDTO(orders, l.TimestampFormat)
+	if limit > 0 && len(orders) == limit {
+		output.NextCursor = EncodeOrderChangeCursor(entity.NextOrderChangeCursor(orders[len(orders)-1]))
+	}
+	return output, nil
+}
+
+// EncodeOrderChangeCursor returns the opaque form of cursor handed to clients.
+func EncodeOrderChangeCursor(cursor entity.OrderChangeCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor.UpdatedAt.UTC().Format(time.RFC3339Nano) + "," + cursor.ID))
+}
+
+// ParseOrderChangeCursor reads a cursor returned by EncodeOrderChangeCursor.
+func ParseOrderChangeCursor(value string) (entity.OrderChangeCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return entity.OrderChangeCursor{}, ErrInvalidCursor
+	}
+	updatedAt, id, ok := strings.Cut(string(raw), ",")
+	if !ok || id == "" {
+		return entity.OrderChangeCursor{}, ErrInvalidCursor
+	}
+	at, err := time.Parse(time.RFC3339Nano, updatedAt)
+	if err != nil {
+		return entity.OrderChangeCursor{}, ErrInvalidCursor
+	}
+	return entity.OrderChangeCursor{UpdatedAt: at, ID: id}, nil
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenChangedOrders_WhenListOrderChanges_ThenShouldKeepTheUpdateOrder(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindUpdatedSince", mock.Anything, entity.OrderChangeCursor{UpdatedAt: since}, 20).Return([]entity.Order{
+		{ID: "first", Price: 10, Tax: 2, UpdatedAt: since.Add(time.Minute)},
+		{ID: "second", Price: 10, Tax: 2, UpdatedAt: since.Add(time.Hour)},
+	}, nil)
+
+	output, err := NewListOrderChangesUseCase(repository).Execute(context.Background(), entity.OrderChangeCursor{UpdatedAt: since}, 20)
+
+	assert.Nil(t, err)
+	assert.Len(t, output.Orders, 2)
+	assert.Equal(t, "first", output.Orders[0].ID)
+	assert.Equal(t, "second", output.Orders[1].ID)
+	assert.Nil(t, output.AsOf)
+	assert.Empty(t, output.NextCursor)
+}
+
+func TestGivenAFullPage_WhenListOrderChanges_ThenShouldReturnTheCursorOfItsLastOrder(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindUpdatedSince", mock.Anything, mock.Anything, 2).Return([]entity.Order{
+		{ID: "a", UpdatedAt: updatedAt},
+		{ID: "b,c", UpdatedAt: updatedAt},
+	}, nil)
+
+	output, err := NewListOrderChangesUseCase(repository).Execute(context.Background(), entity.OrderChangeCursor{}, 2)
+
+	assert.Nil(t, err)
+	cursor, err := ParseOrderChangeCursor(output.NextCursor)
+	assert.Nil(t, err)
+	assert.Equal(t, "b,c", cursor.ID)
+	assert.True(t, updatedAt.Equal(cursor.UpdatedAt))
+}
+
+func TestGivenAForeignCursor_WhenParseOrderChangeCursor_ThenShouldFail(t *testing.T) {
+	for _, value := range []string{"not base64!", "bm8tc2VwYXJhdG9y", EncodeOrderChangeCursor(entity.OrderChangeCursor{})} {
+		_, err := ParseOrderChangeCursor(value)
+
+		assert.ErrorIs(t, err, ErrInvalidCursor, value)
+	}
+}
+
+func TestGivenNoChanges_WhenListOrderChanges_ThenShouldReturnAnEmptyList(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindUpdatedSince", mock.Anything, mock.Anything, mock.Anything).Return([]entity.Order(nil), nil)
+
+	output, err := NewListOrderChangesUseCase(repository).Execute(context.Background(), entity.OrderChangeCursor{UpdatedAt: time.Now()}, 20)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, output.Orders)
+	assert.Empty(t, output.Orders)
+}
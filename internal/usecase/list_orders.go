@@ -1,15 +1,30 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/pkg/pagination"
 )
 
 type ListOrdersOutputDTO struct {
 	Orders []OrderOutputDTO `json:"orders"`
+	// AsOf is the snapshot the page was read from. Clients pass it back to
+	// read the next pages from the same snapshot.
+	AsOf *Timestamp `json:"as_of,omitempty"`
+	// Total is the number of orders across all pages, when requested.
+	Total *int `json:"total,omitempty"`
+	// NextCursor resumes a changes listing after this page. It is empty once
+	// a page comes back short.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type ListOrdersUseCase struct {
 	OrderRepository entity.OrderRepositoryInterface
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
 }
 
 func NewListOrdersUseCase(
@@ -20,22 +35,56 @@ func NewListOrdersUseCase(
 	}
 }
 
-func (l *ListOrdersUseCase) Execute() (ListOrdersOutputDTO, error) {
-	orders, err := l.OrderRepository.FindAll()
+func (l *ListOrdersUseCase) Execute(ctx context.Context, page pagination.Pagination) (ListOrdersOutputDTO, error) {
+	orders, err := l.OrderRepository.FindPage(ctx, page.Limit, page.Offset)
+	if err != nil {
+		return ListOrdersOutputDTO{}, err
+	}
+
+	return newListOrdersOutputDTO(orders, l.TimestampFormat), nil
+}
+
+// ExecuteWithTotal lists the page along with the number of orders across all
+// pages.
+func (l *ListOrdersUseCase) ExecuteWithTotal(ctx context.Context, page pagination.Pagination) (ListOrdersOutputDTO, error) {
+	orders, total, err := l.OrderRepository.FindPageWithTotal(ctx, page.Limit, page.Offset)
+	if err != nil {
+		return ListOrdersOutputDTO{}, err
+	}
+
+	output := newListOrdersOutputDTO(orders, l.TimestampFormat)
+	output.Total = &total
+	return output, nil
+}
+
+// ExecuteAsOf lists the page from the snapshot of the orders created before
+// asOf. A zero asOf starts a new snapshot at the current second.
+func (l *ListOrdersUseCase) ExecuteAsOf(ctx context.Context, page pagination.Pagination, asOf time.Time) (ListOrdersOutputDTO, error) {
+	if asOf.IsZero() {
+		asOf = time.Now().UTC().Truncate(time.Second)
+	}
+	orders, err := l.OrderRepository.FindPageAsOf(ctx, asOf, page.Limit, page.Offset)
 	if err != nil {
 		return ListOrdersOutputDTO{}, err
 	}
 
-	var ordersDTO []OrderOutputDTO
+	output := newListOrdersOutputDTO(orders, l.TimestampFormat)
+	snapshot := NewTimestamp(asOf, l.TimestampFormat)
+	output.AsOf = &snapshot
+	return output, nil
+}
+
+// Version identifies the current state of the orders, so transports can tell
+// whether a list they served before is still fresh.
+func (l *ListOrdersUseCase) Version(ctx context.Context) (entity.OrderSetVersion, error) {
+	return l.OrderRepository.Version(ctx)
+}
+
+func newListOrdersOutputDTO(orders []entity.Order, timestampFormat string) ListOrdersOutputDTO {
+	ordersDTO := make([]OrderOutputDTO, 0, len(orders))
 	for _, order := range orders {
-		orderDTO := OrderOutputDTO{
-			ID:         order.ID,
-			Price:      order.Price,
-			Tax:        order.Tax,
-			FinalPrice: order.Price + order.Tax,
-		}
-		ordersDTO = append(ordersDTO, orderDTO)
+		ordersDTO = append(ordersDTO, newOrderOutputDTO(order, timestampFormat))
 	}
 
-	return ListOrdersOutputDTO{Orders: ordersDTO}, nil
+	return ListOrdersOutputDTO{Orders: ordersDTO}
 }
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+type OrderStatusGroupDTO struct {
+	Status string           `json:"status"`
+	Count  int              `json:"count"`
+	Orders []OrderOutputDTO `json:"orders,omitempty"`
+}
+
+type ListOrdersByStatusOutputDTO struct {
+	Statuses []OrderStatusGroupDTO `json:"statuses"`
+}
+
+type ListOrdersByStatusUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewListOrdersByStatusUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+) *ListOrdersByStatusUseCase {
+	return &ListOrdersByStatusUseCase{
+		OrderRepository: OrderRepository,
+	}
+}
+
+// Execute returns one group per known status, including empty ones. When
+// sample is greater than zero, up to sample orders are attached to each group.
+func (l *ListOrdersByStatusUseCase) Execute(ctx context.Context, sample int) (ListOrdersByStatusOutputDTO, error) {
+	counts, err := l.OrderRepository.CountByStatus(ctx)
+	if err != nil {
+		return ListOrdersByStatusOutputDTO{}, err
+	}
+
+	groups := make([]OrderStatusGroupDTO, 0, len(entity.OrderStatuses))
+	for _, status := range entity.OrderStatuses {
+		group := OrderStatusGroupDTO{
+			Status: status,
+			Count:  counts[status],
+		}
+		if sample > 0 && group.Count > 0 {
+			orders, err := l.OrderRepository.FindByStatus(ctx, status, sample)
+			if err != nil {
+				return ListOrdersByStatusOutputDTO{}, err
+			}
+			for _, order := range orders {
+				group.Orders = append(group.Orders, newOrderOutputDTO(order, l.TimestampFormat))
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	return ListOrdersByStatusOutputDTO{Statuses: groups}, nil
+}
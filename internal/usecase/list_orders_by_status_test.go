@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenGroupedCounts_WhenListOrdersByStatus_ThenShouldReturnEveryKnownStatus(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("CountByStatus", mock.Anything).Return(map[string]int{
+		entity.OrderStatusPending: 2,
+		entity.OrderStatusShipped: 1,
+	}, nil)
+
+	output, err := NewListOrdersByStatusUseCase(repository).Execute(context.Background(), 0)
+
+	assert.Nil(t, err)
+	assert.Len(t, output.Statuses, len(entity.OrderStatuses))
+	counts := make(map[string]int)
+	for _, group := range output.Statuses {
+		counts[group.Status] = group.Count
+		assert.Empty(t, group.Orders)
+	}
+	assert.Equal(t, 2, counts[entity.OrderStatusPending])
+	assert.Equal(t, 1, counts[entity.OrderStatusShipped])
+	assert.Equal(t, 0, counts[entity.OrderStatusDelivered])
+	repository.AssertNotCalled(t, "FindByStatus")
+}
+
+func TestGivenASampleSize_WhenListOrdersByStatus_ThenShouldAttachSamplesToNonEmptyGroups(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("CountByStatus", mock.Anything).Return(map[string]int{entity.OrderStatusPending: 2}, nil)
+	repository.On("FindByStatus", mock.Anything, entity.OrderStatusPending, 1).Return([]entity.Order{
+		{ID: "a", Price: 10, Tax: 2, FinalPrice: 12, Status: entity.OrderStatusPending},
+	}, nil)
+
+	output, err := NewListOrdersByStatusUseCase(repository).Execute(context.Background(), 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, entity.OrderStatusPending, output.Statuses[0].Status)
+	assert.Len(t, output.Statuses[0].Orders, 1)
+	assert.Equal(t, "a", output.Statuses[0].Orders[0].ID)
+	repository.AssertNumberOfCalls(t, "FindByStatus", 1)
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+const (
+	DefaultRecentOrders = 10
+	MaxRecentOrders     = 100
+)
+
+type ListRecentOrdersUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewListRecentOrdersUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+) *ListRecentOrdersUseCase {
+	return &ListRecentOrdersUseCase{
+		OrderRepository: OrderRepository,
+	}
+}
+
+// Execute returns the n most recently created orders, newest first. n defaults
+// to DefaultRecentOrders when not positive and is clamped to MaxRecentOrders.
+func (l *ListRecentOrdersUseCase) Execute(ctx context.Context, n int) (ListOrdersOutputDTO, error) {
+	if n <= 0 {
+		n = DefaultRecentOrders
+	}
+	if n > MaxRecentOrders {
+		n = MaxRecentOrders
+	}
+
+	orders, err := l.OrderRepository.FindRecent(ctx, n)
+	if err != nil {
+		return ListOrdersOutputDTO{}, err
+	}
+
+	ordersDTO := make([]OrderOutputDTO, 0, len(orders))
+	for _, order := range orders {
+		ordersDTO = append(ordersDTO, newOrderOutputDTO(order, l.TimestampFormat))
+	}
+
+	return ListOrdersOutputDTO{Orders: ordersDTO}, nil
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+	"github.com/mvr-garcia/go-clean-arch/internal/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGivenRecentOrders_WhenListRecentOrders_ThenShouldKeepTheRepositoryOrder(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindRecent", mock.Anything, 2).Return([]entity.Order{
+		{ID: "newest", Price: 10, Tax: 2},
+		{ID: "older", Price: 10, Tax: 2},
+	}, nil)
+
+	output, err := NewListRecentOrdersUseCase(repository).Execute(context.Background(), 2)
+
+	assert.Nil(t, err)
+	assert.Len(t, output.Orders, 2)
+	assert.Equal(t, "newest", output.Orders[0].ID)
+	assert.Equal(t, "older", output.Orders[1].ID)
+}
+
+func TestGivenAnOutOfRangeN_WhenListRecentOrders_ThenShouldClampTheLimit(t *testing.T) {
+	repository := &mocks.OrderRepositoryMock{}
+	repository.On("FindRecent", mock.Anything, mock.Anything).Return([]entity.Order{}, nil)
+	useCase := NewListRecentOrdersUseCase(repository)
+
+	_, err := useCase.Execute(context.Background(), 1000)
+	assert.Nil(t, err)
+	_, err = useCase.Execute(context.Background(), 0)
+	assert.Nil(t, err)
+
+	repository.AssertCalled(t, "FindRecent", mock.Anything, MaxRecentOrders)
+	repository.AssertCalled(t, "FindRecent", mock.Anything, DefaultRecentOrders)
+}
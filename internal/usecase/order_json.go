@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"strconv"
+
+	"github.com/mvr-garcia/go-clean-arch/pkg/jsonappend"
+)
+
+// AppendJSON appends the JSON encoding of o to b without reflection. It writes
+// the same bytes as json.Marshal, so the two are interchangeable.
+func (o OrderOutputDTO) AppendJSON(b []byte) ([]byte, error) {
+	var err error
+	b = append(b, `{"id":`...)
+	b = jsonappend.String(b, o.ID)
+	b = append(b, `,"price":`...)
+	if b, err = jsonappend.Float64(b, o.Price); err != nil {
+		return b, err
+	}
+	b = append(b, `,"tax":`...)
+	if b, err = jsonappend.Float64(b, o.Tax); err != nil {
+		return b, err
+	}
+	b = append(b, `,"final_price":`...)
+	if b, err = jsonappend.Float64(b, o.FinalPrice); err != nil {
+		return b, err
+	}
+	b = append(b, `,"status":`...)
+	b = jsonappend.String(b, o.Status)
+	b = append(b, `,"created_at":`...)
+	b = o.CreatedAt.appendJSON(b)
+	b = append(b, `,"updated_at":`...)
+	b = o.UpdatedAt.appendJSON(b)
+	if o.TraceID != "" {
+		b = append(b, `,"trace_id":`...)
+		b = jsonappend.String(b, o.TraceID)
+	}
+	return append(b, '}'), nil
+}
+
+// AppendJSON appends the JSON encoding of l to b without reflection. It writes
+// the same bytes as json.Marshal, so the two are interchangeable.
+func (l ListOrdersOutputDTO) AppendJSON(b []byte) ([]byte, error) {
+	b = append(b, `{"orders":`...)
+	if l.Orders == nil {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, '[')
+		for i, order := range l.Orders {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			var err error
+			if b, err = order.AppendJSON(b); err != nil {
+				return b, err
+			}
+		}
+		b = append(b, ']')
+	}
+	if l.AsOf != nil {
+		b = append(b, `,"as_of":`...)
+		b = l.AsOf.appendJSON(b)
+	}
+	if l.Total != nil {
+		b = append(b, `,"total":`...)
+		b = strconv.AppendInt(b, int64(*l.Total), 10)
+	}
+	if l.NextCursor != "" {
+		b = append(b, `,"next_cursor":`...)
+		b = jsonappend.String(b, l.NextCursor)
+	}
+	return append(b, '}'), nil
+}
+
+func (t Timestamp) appendJSON(b []byte) []byte {
+	return jsonappend.String(b, t.format())
+}
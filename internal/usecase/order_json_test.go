@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newOrderOutputs(n int, layout string) []OrderOutputDTO {
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.FixedZone("BRT", -3*60*60))
+	orders := make([]OrderOutputDTO, n)
+	for i := range orders {
+		orders[i] = OrderOutputDTO{
+			ID:         fmt.Sprintf("order-%d", i),
+			Price:      10.1 * float64(i+1),
+			Tax:        0.1 + 0.2,
+			FinalPrice: 10.1*float64(i+1) + 0.3,
+			Status:     "pending",
+			CreatedAt:  NewTimestamp(created.Add(time.Duration(i)*time.Minute), layout),
+			UpdatedAt:  NewTimestamp(created.Add(time.Duration(i)*time.Hour), layout),
+		}
+		if i%2 == 0 {
+			orders[i].TraceID = fmt.Sprintf("host/req-%06d", i)
+		}
+	}
+	return orders
+}
+
+func TestGivenOrderOutputs_WhenAppendJSON_ThenShouldMatchEncodingJSONByteForByte(t *testing.T) {
+	tricky := newOrderOutputs(1, "")[0]
+	tricky.ID = `<a href="x">&</a>` + " \t\x01"
+	tricky.Price = 1e-7
+	tricky.Tax = 1e21
+	tricky.FinalPrice = 0
+	asOf := NewTimestamp(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), "")
+	total := 42
+
+	for name, output := range map[string]ListOrdersOutputDTO{
+		"nil orders":  {},
+		"no orders":   {Orders: []OrderOutputDTO{}},
+		"page":        {Orders: newOrderOutputs(3, "")},
+		"tricky":      {Orders: []OrderOutputDTO{tricky}},
+		"snapshot":    {Orders: newOrderOutputs(2, ""), AsOf: &asOf},
+		"with totals": {Orders: newOrderOutputs(2, ""), AsOf: &asOf, Total: &total},
+		"changes":     {Orders: newOrderOutputs(2, ""), NextCursor: "MjAyNC0wNS0wMVQxMjowMDowMFosb3JkZXItMQ=="},
+	} {
+		t.Run(name, func(t *testing.T) {
+			want, err := json.Marshal(output)
+			assert.Nil(t, err)
+			got, err := output.AppendJSON(nil)
+			assert.Nil(t, err)
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestGivenACustomTimestampFormat_WhenAppendJSON_ThenShouldMatchEncodingJSON(t *testing.T) {
+	output := newOrderOutputs(1, "2006-01-02 15:04:05 <MST>")[0]
+
+	want, err := json.Marshal(output)
+	assert.Nil(t, err)
+	got, err := output.AppendJSON(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestGivenAnInfinitePrice_WhenAppendJSON_ThenShouldFailLikeEncodingJSON(t *testing.T) {
+	output := newOrderOutputs(1, "")[0]
+	output.Price = math.Inf(1)
+
+	_, err := output.AppendJSON(nil)
+
+	assert.NotNil(t, err)
+}
+
+func BenchmarkListOrdersOutputDTOEncodingJSON(b *testing.B) {
+	output := ListOrdersOutputDTO{Orders: newOrderOutputs(100, "")}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(output); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListOrdersOutputDTOAppendJSON(b *testing.B) {
+	output := ListOrdersOutputDTO{Orders: newOrderOutputs(100, "")}
+	buf := make([]byte, 0, 32<<10)
+	b.ReportAllocs()
+	for b.Loop() {
+		var err error
+		if buf, err = output.AppendJSON(buf[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+type SearchOrdersByIDPrefixUseCase struct {
+	OrderRepository entity.OrderRepositoryInterface
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewSearchOrdersByIDPrefixUseCase(
+	OrderRepository entity.OrderRepositoryInterface,
+) *SearchOrdersByIDPrefixUseCase {
+	return &SearchOrdersByIDPrefixUseCase{
+		OrderRepository: OrderRepository,
+	}
+}
+
+// Execute returns up to limit orders whose ID starts with prefix, sorted by ID.
+// It returns entity.ErrIDPrefixTooShort when prefix is too short to search.
+func (s *SearchOrdersByIDPrefixUseCase) Execute(ctx context.Context, prefix string, limit int) (ListOrdersOutputDTO, error) {
+	orders, err := s.OrderRepository.SearchByIDPrefix(ctx, prefix, limit)
+	if err != nil {
+		return ListOrdersOutputDTO{}, err
+	}
+
+	ordersDTO := make([]OrderOutputDTO, 0, len(orders))
+	for _, order := range orders {
+		ordersDTO = append(ordersDTO, newOrderOutputDTO(order, s.TimestampFormat))
+	}
+
+	return ListOrdersOutputDTO{Orders: ordersDTO}, nil
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/mvr-garcia/go-clean-arch/internal/entity"
+)
+
+type StreamOrdersUseCase struct {
+	OrderIterator entity.OrderIterator
+	// TimestampFormat lays out the output timestamps; empty means
+	// DefaultTimestampFormat.
+	TimestampFormat string
+}
+
+func NewStreamOrdersUseCase(OrderIterator entity.OrderIterator) *StreamOrdersUseCase {
+	return &StreamOrdersUseCase{OrderIterator: OrderIterator}
+}
+
+// Execute writes every order to w as newline-delimited JSON, each line the
+// OrderOutputDTO a list would hold. Every order is written with one Write as
+// soon as it is read, so a w that flushes on Write delivers them one by one.
+// An error after the first order leaves the output truncated.
+func (s *StreamOrdersUseCase) Execute(ctx context.Context, w io.Writer) error {
+	var line bytes.Buffer
+	encoder := json.NewEncoder(&line)
+	for order, err := range s.OrderIterator.Orders(ctx) {
+		if err != nil {
+			return err
+		}
+		line.Reset()
+		if err := encoder.Encode(newOrderOutputDTO(order, s.TimestampFormat)); err != nil {
+			return err
+		}
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
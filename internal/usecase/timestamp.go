@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DefaultTimestampFormat is the layout of Timestamps created without one.
+const DefaultTimestampFormat = time.RFC3339
+
+// Timestamp is a time.Time that is always marshaled in UTC using its layout.
+type Timestamp struct {
+	time   time.Time
+	layout string
+}
+
+// NewTimestamp returns t to be marshaled with layout, or with
+// DefaultTimestampFormat when layout is empty.
+func NewTimestamp(t time.Time, layout string) Timestamp {
+	return Timestamp{time: t, layout: layout}
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.format())
+}
+
+func (t Timestamp) Time() time.Time {
+	return t.time
+}
+
+func (t Timestamp) format() string {
+	layout := t.layout
+	if layout == "" {
+		layout = DefaultTimestampFormat
+	}
+	return t.time.UTC().Format(layout)
+}
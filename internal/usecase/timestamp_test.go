@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenAnOrderOutput_WhenMarshalJSON_ThenTimestampsShouldBeRFC3339UTC(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+	output := OrderOutputDTO{
+		ID:         "123",
+		Price:      10.0,
+		Tax:        2.0,
+		FinalPrice: 12.0,
+		Status:     "pending",
+		CreatedAt:  NewTimestamp(time.Date(2024, 5, 1, 9, 30, 0, 0, saoPaulo), ""),
+		UpdatedAt:  NewTimestamp(time.Date(2024, 5, 1, 12, 45, 10, 0, time.UTC), ""),
+	}
+
+	body, err := json.Marshal(output)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id": "123",
+		"price": 10,
+		"tax": 2,
+		"final_price": 12,
+		"status": "pending",
+		"created_at": "2024-05-01T12:30:00Z",
+		"updated_at": "2024-05-01T12:45:10Z"
+	}`, string(body))
+}
+
+func TestGivenACustomTimestampFormat_WhenMarshalJSON_ThenShouldUseIt(t *testing.T) {
+	body, err := json.Marshal(NewTimestamp(time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), "2006-01-02 15:04:05"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, `"2024-05-01 12:30:00"`, string(body))
+}
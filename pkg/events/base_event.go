@@ -0,0 +1,56 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdentifiedEventInterface is implemented by events that carry their own ID,
+// which consumers can use to drop duplicate deliveries.
+type IdentifiedEventInterface interface {
+	EventInterface
+	GetEventID() string
+}
+
+// BaseEvent gives the events embedding it a unique EventID and the time the
+// event occurred. The constructor of the event sets them through NewBaseEvent;
+// events that are dispatched more than once are cloned through NewFrom so each
+// dispatch gets its own.
+type BaseEvent struct {
+	EventID    string
+	OccurredAt time.Time
+}
+
+func NewBaseEvent() BaseEvent {
+	return BaseEvent{
+		EventID:    uuid.NewString(),
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+func (e *BaseEvent) GetEventID() string {
+	return e.EventID
+}
+
+func (e *BaseEvent) GetDateTime() time.Time {
+	return e.OccurredAt
+}
+
+// ClonableEventInterface is implemented by events that can copy themselves
+// with a new BaseEvent and no payload.
+type ClonableEventInterface interface {
+	EventInterface
+	Clone() EventInterface
+}
+
+// NewFrom returns a fresh copy of template when it is clonable, and template
+// itself otherwise. Use cases keep one configured event as a template and
+// dispatch a copy each time, so concurrent requests never share, and race on,
+// the payload or the ID of the same value.
+func NewFrom(template EventInterface) EventInterface {
+	if clonable, ok := template.(ClonableEventInterface); ok {
+		return clonable.Clone()
+	}
+	return template
+}
@@ -1,35 +1,126 @@
 package events
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
+	"time"
 )
 
 var ErrHandlerAlreadyRegistered = errors.New("handler already registered")
+var ErrTooManyHandlers = errors.New("too many handlers registered for event")
+var ErrDispatcherClosed = errors.New("event dispatcher is closed")
 
 type EventDispatcher struct {
-	handlers map[string][]EventHandlerInterface
+	handlers    map[string][]EventHandlerInterface
+	maxHandlers int
+	slowAfter   time.Duration
+	logger      *slog.Logger
+
+	// mu guards the fields above and closed so handlers can be registered
+	// from several goroutines while events are dispatched.
+	mu       sync.RWMutex
+	closed   bool
+	inFlight sync.WaitGroup
 }
 
+var _ EventDispatcherInterface = (*EventDispatcher)(nil)
+
 func NewEventDispatcher() *EventDispatcher {
 	return &EventDispatcher{
 		handlers: make(map[string][]EventHandlerInterface),
 	}
 }
 
+// SetMaxHandlers limits how many handlers can be registered for a single event.
+// Zero, the default, means no limit.
+func (ed *EventDispatcher) SetMaxHandlers(max int) {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+	ed.maxHandlers = max
+}
+
+// WarnSlowHandlers logs a warning to logger for every handler that takes
+// longer than threshold to handle an event. Zero, the default, disables it.
+func (ed *EventDispatcher) WarnSlowHandlers(threshold time.Duration, logger *slog.Logger) {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+	ed.slowAfter = threshold
+	ed.logger = logger
+}
+
+// Dispatch runs every handler registered for the event and waits for them. It
+// returns ErrDispatcherClosed once Close has been called.
 func (ev *EventDispatcher) Dispatch(event EventInterface) error {
-	if handlers, ok := ev.handlers[event.GetName()]; ok {
+	ev.mu.RLock()
+	if ev.closed {
+		ev.mu.RUnlock()
+		return ErrDispatcherClosed
+	}
+	ev.inFlight.Add(1)
+	handlers, ok := ev.handlers[event.GetName()]
+	slowAfter, logger := ev.slowAfter, ev.logger
+	ev.mu.RUnlock()
+	defer ev.inFlight.Done()
+
+	if ok {
 		wg := &sync.WaitGroup{}
 		for _, handler := range handlers {
 			wg.Add(1)
-			go handler.Handle(event, wg)
+			if slowAfter > 0 && logger != nil {
+				go handleTimed(event, handler, wg, slowAfter, logger)
+			} else {
+				go handler.Handle(event, wg)
+			}
 		}
 		wg.Wait()
 	}
 	return nil
 }
 
+// handleTimed runs handler with its own WaitGroup so the time until it calls
+// Done is measured, even when it finishes asynchronously.
+func handleTimed(event EventInterface, handler EventHandlerInterface, wg *sync.WaitGroup, slowAfter time.Duration, logger *slog.Logger) {
+	defer wg.Done()
+	start := time.Now()
+	handlerWG := &sync.WaitGroup{}
+	handlerWG.Add(1)
+	handler.Handle(event, handlerWG)
+	handlerWG.Wait()
+	if elapsed := time.Since(start); elapsed > slowAfter {
+		logger.Warn("slow event handler",
+			"event", event.GetName(),
+			"handler", fmt.Sprintf("%T", handler),
+			"duration", elapsed,
+		)
+	}
+}
+
+// Close rejects new dispatches and waits for the ones in flight, or for ctx to
+// be done.
+func (ed *EventDispatcher) Close(ctx context.Context) error {
+	ed.mu.Lock()
+	ed.closed = true
+	ed.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		ed.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (ed *EventDispatcher) Register(eventName string, handler EventHandlerInterface) error {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
 	if _, ok := ed.handlers[eventName]; ok {
 		for _, h := range ed.handlers[eventName] {
 			if h == handler {
@@ -37,11 +128,16 @@ func (ed *EventDispatcher) Register(eventName string, handler EventHandlerInterf
 			}
 		}
 	}
+	if ed.maxHandlers > 0 && len(ed.handlers[eventName]) >= ed.maxHandlers {
+		return ErrTooManyHandlers
+	}
 	ed.handlers[eventName] = append(ed.handlers[eventName], handler)
 	return nil
 }
 
 func (ed *EventDispatcher) Has(eventName string, handler EventHandlerInterface) bool {
+	ed.mu.RLock()
+	defer ed.mu.RUnlock()
 	if _, ok := ed.handlers[eventName]; ok {
 		for _, h := range ed.handlers[eventName] {
 			if h == handler {
@@ -52,11 +148,17 @@ func (ed *EventDispatcher) Has(eventName string, handler EventHandlerInterface)
 	return false
 }
 
+// Remove builds a new handler slice rather than shifting the old one in place,
+// so dispatches already iterating over it are unaffected.
 func (ed *EventDispatcher) Remove(eventName string, handler EventHandlerInterface) error {
-	if _, ok := ed.handlers[eventName]; ok {
-		for i, h := range ed.handlers[eventName] {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+	if handlers, ok := ed.handlers[eventName]; ok {
+		for i, h := range handlers {
 			if h == handler {
-				ed.handlers[eventName] = append(ed.handlers[eventName][:i], ed.handlers[eventName][i+1:]...)
+				remaining := make([]EventHandlerInterface, 0, len(handlers)-1)
+				remaining = append(remaining, handlers[:i]...)
+				ed.handlers[eventName] = append(remaining, handlers[i+1:]...)
 				return nil
 			}
 		}
@@ -65,5 +167,7 @@ func (ed *EventDispatcher) Remove(eventName string, handler EventHandlerInterfac
 }
 
 func (ed *EventDispatcher) Clear() {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
 	ed.handlers = make(map[string][]EventHandlerInterface)
 }
@@ -1,6 +1,9 @@
 package events
 
 import (
+	"bytes"
+	"context"
+	"log/slog"
 	"sync"
 	"testing"
 	"time"
@@ -86,6 +89,33 @@ func (suite *EventDispatcherTestSuite) TestEventDispatcher_Register_WithSameHand
 	suite.Equal(1, len(suite.eventDispatcher.handlers[suite.event.GetName()]))
 }
 
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Register_WithMaxHandlers() {
+	suite.eventDispatcher.SetMaxHandlers(2)
+
+	err := suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	suite.Nil(err)
+	err = suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler2)
+	suite.Nil(err)
+
+	err = suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler3)
+	suite.Equal(ErrTooManyHandlers, err)
+	suite.Equal(2, len(suite.eventDispatcher.handlers[suite.event.GetName()]))
+
+	// the limit applies per event
+	err = suite.eventDispatcher.Register(suite.event2.GetName(), &suite.handler3)
+	suite.Nil(err)
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_Register_WithSameHandlerAtMaxHandlers() {
+	suite.eventDispatcher.SetMaxHandlers(1)
+
+	err := suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	suite.Nil(err)
+
+	err = suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
+	suite.Equal(ErrHandlerAlreadyRegistered, err)
+}
+
 func (suite *EventDispatcherTestSuite) TestEventDispatcher_Clear() {
 	// Event 1
 	err := suite.eventDispatcher.Register(suite.event.GetName(), &suite.handler)
@@ -172,6 +202,132 @@ func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch() {
 	eh2.AssertNumberOfCalls(suite.T(), "Handle", 1)
 }
 
+func (suite *EventDispatcherTestSuite) TestEventDispatch_DispatchAfterClose() {
+	eh := &MockHandler{}
+	suite.eventDispatcher.Register(suite.event.GetName(), eh)
+
+	suite.Nil(suite.eventDispatcher.Close(context.Background()))
+
+	suite.ErrorIs(suite.eventDispatcher.Dispatch(&suite.event), ErrDispatcherClosed)
+	eh.AssertNotCalled(suite.T(), "Handle", mock.Anything)
+}
+
+// blockingHandler holds its dispatch until release is closed.
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) Handle(event EventInterface, wg *sync.WaitGroup) {
+	close(h.started)
+	<-h.release
+	wg.Done()
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatch_CloseWaitsForInFlightDispatches() {
+	eh := &blockingHandler{started: make(chan struct{}), release: make(chan struct{})}
+	suite.eventDispatcher.Register(suite.event.GetName(), eh)
+	go suite.eventDispatcher.Dispatch(&suite.event)
+	<-eh.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	suite.ErrorIs(suite.eventDispatcher.Close(ctx), context.DeadlineExceeded)
+
+	close(eh.release)
+	suite.Nil(suite.eventDispatcher.Close(context.Background()))
+}
+
+// Run with -race: registering, dispatching and removing from several
+// goroutines must not race on the handler map.
+func (suite *EventDispatcherTestSuite) TestEventDispatcher_RegisterConcurrently() {
+	const registrations = 50
+	handlers := make([]*MockHandler, registrations)
+	for i := range handlers {
+		handlers[i] = &MockHandler{}
+		handlers[i].On("Handle", mock.Anything)
+	}
+
+	var wg sync.WaitGroup
+	for _, handler := range handlers {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			suite.Nil(suite.eventDispatcher.Register(suite.event.GetName(), handler))
+		}()
+		go func() {
+			defer wg.Done()
+			suite.Nil(suite.eventDispatcher.Dispatch(&suite.event))
+		}()
+		go func() {
+			defer wg.Done()
+			suite.eventDispatcher.Has(suite.event.GetName(), handler)
+			suite.Nil(suite.eventDispatcher.Remove(suite.event2.GetName(), handler))
+		}()
+	}
+	wg.Wait()
+
+	suite.Len(suite.eventDispatcher.handlers[suite.event.GetName()], registrations)
+	for _, handler := range handlers {
+		suite.True(suite.eventDispatcher.Has(suite.event.GetName(), handler))
+	}
+}
+
+type sleepyHandler struct {
+	sleep time.Duration
+}
+
+func (h *sleepyHandler) Handle(event EventInterface, wg *sync.WaitGroup) {
+	time.Sleep(h.sleep)
+	wg.Done()
+}
+
+func (suite *EventDispatcherTestSuite) TestEventDispatch_WarnSlowHandlers() {
+	var logs bytes.Buffer
+	suite.eventDispatcher.WarnSlowHandlers(20*time.Millisecond, slog.New(slog.NewTextHandler(&logs, nil)))
+	suite.eventDispatcher.Register(suite.event.GetName(), &sleepyHandler{sleep: 50 * time.Millisecond})
+	suite.eventDispatcher.Register(suite.event2.GetName(), &sleepyHandler{})
+
+	suite.Nil(suite.eventDispatcher.Dispatch(&suite.event))
+	suite.Nil(suite.eventDispatcher.Dispatch(&suite.event2))
+
+	suite.Contains(logs.String(), "level=WARN")
+	suite.Contains(logs.String(), `msg="slow event handler" event=test handler=*events.sleepyHandler duration=`)
+	suite.NotContains(logs.String(), "event=test2")
+}
+
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(EventDispatcherTestSuite))
 }
+
+type identifiedEvent struct {
+	BaseEvent
+	payload interface{}
+}
+
+func (e *identifiedEvent) GetName() string                { return "identified" }
+func (e *identifiedEvent) GetPayload() interface{}        { return e.payload }
+func (e *identifiedEvent) SetPayload(payload interface{}) { e.payload = payload }
+func (e *identifiedEvent) Clone() EventInterface          { return &identifiedEvent{BaseEvent: NewBaseEvent()} }
+
+func TestGivenAClonableTemplate_WhenNewFrom_ThenEachCopyShouldCarryANewEventIDAndNoPayload(t *testing.T) {
+	template := &identifiedEvent{BaseEvent: NewBaseEvent()}
+	template.SetPayload("template")
+
+	first := NewFrom(template).(*identifiedEvent)
+	second := NewFrom(template).(*identifiedEvent)
+
+	assert.NotSame(t, template, first)
+	assert.NotEmpty(t, first.GetEventID())
+	assert.NotEqual(t, template.GetEventID(), first.GetEventID())
+	assert.NotEqual(t, first.GetEventID(), second.GetEventID())
+	assert.False(t, first.GetDateTime().IsZero())
+	assert.Nil(t, first.GetPayload())
+	assert.Equal(t, "template", template.GetPayload())
+}
+
+func TestGivenAnEventThatCannotBeCloned_WhenNewFrom_ThenShouldReturnItAsIs(t *testing.T) {
+	event := &TestEvent{Name: "test"}
+
+	assert.Same(t, event, NewFrom(event))
+}
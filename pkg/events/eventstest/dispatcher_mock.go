@@ -0,0 +1,44 @@
+// Package eventstest provides test doubles for the events package.
+package eventstest
+
+import (
+	"context"
+
+	"github.com/mvr-garcia/go-clean-arch/pkg/events"
+	"github.com/stretchr/testify/mock"
+)
+
+type DispatcherMock struct {
+	mock.Mock
+}
+
+var _ events.EventDispatcherInterface = (*DispatcherMock)(nil)
+
+func (m *DispatcherMock) Register(eventName string, handler events.EventHandlerInterface) error {
+	args := m.Called(eventName, handler)
+	return args.Error(0)
+}
+
+func (m *DispatcherMock) Dispatch(event events.EventInterface) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *DispatcherMock) Remove(eventName string, handler events.EventHandlerInterface) error {
+	args := m.Called(eventName, handler)
+	return args.Error(0)
+}
+
+func (m *DispatcherMock) Has(eventName string, handler events.EventHandlerInterface) bool {
+	args := m.Called(eventName, handler)
+	return args.Bool(0)
+}
+
+func (m *DispatcherMock) Clear() {
+	m.Called()
+}
+
+func (m *DispatcherMock) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
@@ -1,6 +1,7 @@
 package events
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -22,4 +23,7 @@ type EventDispatcherInterface interface {
 	Remove(eventName string, handler EventHandlerInterface) error
 	Has(eventName string, handler EventHandlerInterface) bool
 	Clear()
+	// Close stops accepting events; later dispatches fail with
+	// ErrDispatcherClosed.
+	Close(ctx context.Context) error
 }
@@ -0,0 +1,35 @@
+package events
+
+import "context"
+
+// NoopDispatcher satisfies EventDispatcherInterface without delivering events.
+// It is used when no dispatcher is wired and by tests that don't care about events.
+type NoopDispatcher struct{}
+
+var _ EventDispatcherInterface = (*NoopDispatcher)(nil)
+
+func NewNoopDispatcher() *NoopDispatcher {
+	return &NoopDispatcher{}
+}
+
+func (d *NoopDispatcher) Register(eventName string, handler EventHandlerInterface) error {
+	return nil
+}
+
+func (d *NoopDispatcher) Dispatch(event EventInterface) error {
+	return nil
+}
+
+func (d *NoopDispatcher) Remove(eventName string, handler EventHandlerInterface) error {
+	return nil
+}
+
+func (d *NoopDispatcher) Has(eventName string, handler EventHandlerInterface) bool {
+	return false
+}
+
+func (d *NoopDispatcher) Clear() {}
+
+func (d *NoopDispatcher) Close(ctx context.Context) error {
+	return nil
+}
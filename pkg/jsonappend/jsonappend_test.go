@@ -0,0 +1,43 @@
+package jsonappend
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenTrickyStrings_WhenString_ThenShouldMatchEncodingJSON(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"order-123",
+		`quote " and backslash \`,
+		"<script>&amp;</script>",
+		"tab\tnewline\ncarriage\rbell\a\b\f\x00\x1f",
+		"café ünïcödé 日本語 🚀",
+		"line\u2028paragraph\u2029",
+		"invalid \xff\xfe utf-8",
+	} {
+		want, err := json.Marshal(s)
+		assert.Nil(t, err)
+		assert.Equal(t, string(want), string(String(nil, s)), s)
+	}
+}
+
+func TestGivenFloats_WhenFloat64_ThenShouldMatchEncodingJSON(t *testing.T) {
+	for _, f := range []float64{0, -0.5, 1, 12.5, 0.1 + 0.2, 1234567.891, 1e-6, 1e-7, 123e-9, 1e20, 1e21, -1e21, math.MaxFloat64, math.SmallestNonzeroFloat64} {
+		want, err := json.Marshal(f)
+		assert.Nil(t, err)
+		got, err := Float64(nil, f)
+		assert.Nil(t, err)
+		assert.Equal(t, string(want), string(got), f)
+	}
+}
+
+func TestGivenNaNOrInfinity_WhenFloat64_ThenShouldFail(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := Float64(nil, f)
+		assert.NotNil(t, err)
+	}
+}
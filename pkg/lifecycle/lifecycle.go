@@ -0,0 +1,89 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Hook is a background component managed by a Registry. Start must not block;
+// Stop should return once the component has shut down or ctx is done. Either
+// may be nil.
+type Hook struct {
+	Name  string
+	Start func() error
+	Stop  func(ctx context.Context) error
+}
+
+// Registry starts components in registration order and stops them in reverse.
+type Registry struct {
+	// Logger, when set, logs every component that fails to stop as it fails,
+	// so each failure is visible even if the joined error is not.
+	Logger *slog.Logger
+
+	hooks   []Hook
+	started int
+	failed  chan error
+}
+
+func NewRegistry() *Registry {
+	return &Registry{failed: make(chan error, 1)}
+}
+
+// Go runs serve in the background for a component its Start hook launched. An
+// error serve returns is reported on Failed, so the process can shut down
+// instead of running on without the component. Only the first is kept.
+func (r *Registry) Go(name string, serve func() error) {
+	go func() {
+		if err := serve(); err != nil {
+			select {
+			case r.failed <- fmt.Errorf("%s: %w", name, err):
+			default:
+			}
+		}
+	}()
+}
+
+// Failed receives the first error returned by a function passed to Go.
+func (r *Registry) Failed() <-chan error {
+	return r.failed
+}
+
+func (r *Registry) Register(name string, start func() error, stop func(ctx context.Context) error) {
+	r.hooks = append(r.hooks, Hook{Name: name, Start: start, Stop: stop})
+}
+
+// Start starts every registered component. If one fails, the components
+// already started are stopped, in reverse order, before the error is returned.
+func (r *Registry) Start(ctx context.Context) error {
+	for _, hook := range r.hooks[r.started:] {
+		if hook.Start != nil {
+			if err := hook.Start(); err != nil {
+				err = fmt.Errorf("start %s: %w", hook.Name, err)
+				return errors.Join(err, r.Stop(ctx))
+			}
+		}
+		r.started++
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse order. Every component is given
+// the chance to stop; their errors are joined.
+func (r *Registry) Stop(ctx context.Context) error {
+	var errs []error
+	for ; r.started > 0; r.started-- {
+		hook := r.hooks[r.started-1]
+		if hook.Stop == nil {
+			continue
+		}
+		if err := hook.Stop(ctx); err != nil {
+			if r.Logger != nil {
+				r.Logger.Error("component failed to stop", "component", hook.Name, "error", err)
+			}
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
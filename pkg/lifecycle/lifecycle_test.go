@@ -0,0 +1,111 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) register(registry *Registry, name string, startErr, stopErr error) {
+	registry.Register(name,
+		func() error {
+			r.calls = append(r.calls, "start "+name)
+			return startErr
+		},
+		func(ctx context.Context) error {
+			r.calls = append(r.calls, "stop "+name)
+			return stopErr
+		},
+	)
+}
+
+func TestGivenRegisteredComponents_WhenStartAndStop_ThenShouldStopInReverseOrder(t *testing.T) {
+	registry := NewRegistry()
+	calls := &recorder{}
+	calls.register(registry, "db", nil, nil)
+	calls.register(registry, "web", nil, nil)
+	calls.register(registry, "grpc", nil, nil)
+
+	assert.Nil(t, registry.Start(context.Background()))
+	assert.Nil(t, registry.Stop(context.Background()))
+
+	assert.Equal(t, []string{"start db", "start web", "start grpc", "stop grpc", "stop web", "stop db"}, calls.calls)
+}
+
+func TestGivenFailingStops_WhenStop_ThenShouldStopEveryComponentAndJoinTheErrors(t *testing.T) {
+	registry := NewRegistry()
+	calls := &recorder{}
+	webErr := errors.New("web failed")
+	dbErr := errors.New("db failed")
+	calls.register(registry, "db", nil, dbErr)
+	calls.register(registry, "web", nil, webErr)
+	calls.register(registry, "grpc", nil, nil)
+	assert.Nil(t, registry.Start(context.Background()))
+
+	err := registry.Stop(context.Background())
+
+	assert.ErrorIs(t, err, webErr)
+	assert.ErrorIs(t, err, dbErr)
+	assert.EqualError(t, err, "stop web: web failed\nstop db: db failed")
+	assert.Equal(t, []string{"start db", "start web", "start grpc", "stop grpc", "stop web", "stop db"}, calls.calls)
+}
+
+func TestGivenALogger_WhenTwoComponentsFailToStop_ThenShouldLogEachFailure(t *testing.T) {
+	registry := NewRegistry()
+	var logs bytes.Buffer
+	registry.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	calls := &recorder{}
+	calls.register(registry, "database", nil, errors.New("connection reset"))
+	calls.register(registry, "RabbitMQ", nil, errors.New("channel closed"))
+	calls.register(registry, "web server", nil, nil)
+	assert.Nil(t, registry.Start(context.Background()))
+
+	err := registry.Stop(context.Background())
+
+	assert.EqualError(t, err, "stop RabbitMQ: channel closed\nstop database: connection reset")
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `component=RabbitMQ error="channel closed"`)
+	assert.Contains(t, lines[1], `component=database error="connection reset"`)
+}
+
+func TestGivenAFailingStart_WhenStart_ThenShouldStopTheComponentsAlreadyStarted(t *testing.T) {
+	registry := NewRegistry()
+	calls := &recorder{}
+	startErr := errors.New("port in use")
+	calls.register(registry, "db", nil, nil)
+	calls.register(registry, "web", startErr, nil)
+	calls.register(registry, "grpc", nil, nil)
+
+	err := registry.Start(context.Background())
+
+	assert.ErrorIs(t, err, startErr)
+	assert.Equal(t, []string{"start db", "start web", "stop db"}, calls.calls)
+	assert.Nil(t, registry.Stop(context.Background()))
+}
+
+func TestGivenABackgroundServeThatFails_WhenGo_ThenFailedShouldReportTheFirstError(t *testing.T) {
+	registry := NewRegistry()
+	serveErr := errors.New("accept: too many open files")
+
+	registry.Go("web", func() error { return serveErr })
+	registry.Go("grpc", func() error { return nil })
+
+	select {
+	case err := <-registry.Failed():
+		assert.ErrorIs(t, err, serveErr)
+		assert.EqualError(t, err, "web: accept: too many open files")
+	case <-time.After(time.Second):
+		t.Fatal("the serve error was not reported")
+	}
+}
@@ -0,0 +1,66 @@
+package pagination
+
+import "errors"
+
+// DefaultLimit and MaxLimit are the page sizes used by Limits left unset.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Limits holds the page sizes a transport applies: Default when no limit is
+// given, and Max to cap any larger request. Each transport is given its own,
+// set from PAGINATION_DEFAULT_LIMIT and PAGINATION_MAX_LIMIT; a zero field
+// falls back to DefaultLimit or MaxLimit.
+type Limits struct {
+	Default int
+	Max     int
+}
+
+var ErrNegativeLimit = errors.New("limit must not be negative")
+var ErrNegativeOffset = errors.New("offset must not be negative")
+
+type Pagination struct {
+	Limit  int
+	Offset int
+}
+
+// Parse validates limit and offset as received from any transport with the
+// package defaults. See Limits.Parse.
+func Parse(limit, offset int) (Pagination, error) {
+	return Limits{}.Parse(limit, offset)
+}
+
+// Parse validates limit and offset as received from any transport. A zero
+// limit means "not set" and falls back to l.Default; limits above l.Max are
+// clamped rather than rejected.
+func (l Limits) Parse(limit, offset int) (Pagination, error) {
+	if limit < 0 {
+		return Pagination{}, ErrNegativeLimit
+	}
+	if offset < 0 {
+		return Pagination{}, ErrNegativeOffset
+	}
+	if limit == 0 {
+		limit = l.defaultLimit()
+	}
+	if limit > l.MaxLimit() {
+		limit = l.MaxLimit()
+	}
+	return Pagination{Limit: limit, Offset: offset}, nil
+}
+
+// MaxLimit is the largest page size l accepts.
+func (l Limits) MaxLimit() int {
+	if l.Max == 0 {
+		return MaxLimit
+	}
+	return l.Max
+}
+
+func (l Limits) defaultLimit() int {
+	if l.Default == 0 {
+		return DefaultLimit
+	}
+	return l.Default
+}
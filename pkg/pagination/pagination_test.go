@@ -0,0 +1,48 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGivenNoLimit_WhenParse_ThenShouldUseTheDefault(t *testing.T) {
+	page, err := Parse(0, 0)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Pagination{Limit: DefaultLimit, Offset: 0}, page)
+}
+
+func TestGivenALimitAboveTheMax_WhenParse_ThenShouldClampIt(t *testing.T) {
+	page, err := Parse(MaxLimit+1, 40)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Pagination{Limit: MaxLimit, Offset: 40}, page)
+}
+
+func TestGivenAValidLimit_WhenParse_ThenShouldKeepIt(t *testing.T) {
+	page, err := Parse(5, 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Pagination{Limit: 5, Offset: 10}, page)
+}
+
+func TestGivenNegativeValues_WhenParse_ThenShouldReturnAnError(t *testing.T) {
+	_, err := Parse(-1, 0)
+	assert.ErrorIs(t, err, ErrNegativeLimit)
+
+	_, err = Parse(10, -1)
+	assert.ErrorIs(t, err, ErrNegativeOffset)
+}
+
+func TestGivenLimits_WhenParse_ThenShouldUseThemInsteadOfThePackageDefaults(t *testing.T) {
+	limits := Limits{Default: 5, Max: 10}
+
+	page, err := limits.Parse(0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, Pagination{Limit: 5, Offset: 0}, page)
+
+	page, err = limits.Parse(50, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, Pagination{Limit: 10, Offset: 0}, page)
+}